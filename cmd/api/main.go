@@ -2,6 +2,7 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"github.com/gin-gonic/gin"
@@ -14,12 +15,26 @@ func main() {
 	// Load environment variables from .env file if present
 	_ = godotenv.Load()
 
-	if err := config.InitRedis(); err != nil {
-		log.Fatalf("failed to connect to Redis: %v", err)
+	// CONFIG_STORE selects where the pack configuration lives (redis, memory or file)
+	store, err := config.NewStoreFromEnv()
+	if err != nil {
+		log.Fatalf("failed to open config store: %v", err)
 	}
 
+	// Seed the default pack sizes into an empty store and serve them until it has some
+	defaults, err := config.DefaultsFromEnv()
+	if err != nil {
+		log.Fatalf("invalid default pack sizes: %v", err)
+	}
+	if seeded, err := config.Seed(context.Background(), store, defaults); err != nil {
+		log.Printf("could not seed default pack sizes, serving them as fallback: %v", err)
+	} else if seeded != nil {
+		log.Printf("seeded default pack sizes %v as version %d", seeded.Sizes, seeded.Version)
+	}
+	store = config.WithDefaults(store, defaults)
+
 	r := gin.Default()
-	http.RegisterRoutes(r)
+	http.RegisterRoutes(r, store)
 
 	if err := r.Run(":8080"); err != nil {
 		log.Fatalf("failed to start server: %v", err)
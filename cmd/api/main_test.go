@@ -1,7 +1,6 @@
 package main
 
 import (
-	"github.com/rapido-liebre/pack_solver/internal/config"
 	"net/http"
 	"os"
 	"testing"
@@ -11,14 +10,10 @@ import (
 )
 
 func TestMainHealthEndpoint(t *testing.T) {
-	// Setup environment variables
-	_ = os.Setenv("REDIS_ADDR", "localhost:6379")
+	// Setup environment variables; the in-memory store needs no Redis
+	_ = os.Setenv("CONFIG_STORE", "memory")
 	_ = os.Setenv("PACK_SOLVER_API", "http://localhost:8080")
 
-	// Initialize Redis
-	err := config.InitRedis()
-	assert.NoError(t, err)
-
 	// Run app as goroutine
 	go func() {
 		main()
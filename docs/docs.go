@@ -17,7 +17,7 @@ const docTemplate = `{
     "paths": {
         "/config/packs": {
             "get": {
-                "description": "Returns the list of configured pack sizes fetched from Redis",
+                "description": "Returns the configured pack sizes, costs and packaging. The ETag header holds the config version,\nto send back in If-Match when updating.",
                 "consumes": [
                     "application/json"
                 ],
@@ -32,12 +32,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "type": "integer"
-                                }
+                            "$ref": "#/definitions/http.PackConfigResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Config version, e.g. \\\"3\\"
                             }
                         }
                     },
@@ -73,6 +73,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/http.PackConfigRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version the change is based on",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -80,6 +86,12 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/http.PackConfigResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version created by the change"
+                            }
                         }
                     },
                     "400": {
@@ -91,6 +103,13 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -103,9 +122,149 @@ const docTemplate = `{
                 }
             }
         },
-        "/order": {
+        "/config/packs/analysis": {
+            "get": {
+                "description": "Reports the GCD of the stored pack sizes, the largest quantity that cannot be filled exactly\n(Frobenius number, null when the GCD is above 1) and the worst-case overage over all quantities.\nWith from and to the overage is also summarized for that quantity range (at most 16777216 quantities).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Analyze the pack configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Analyze the configuration of this product instead of the global one",
+                        "name": "sku",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "First quantity of the range",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Last quantity of the range",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.AnalysisResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/config/packs/history": {
+            "get": {
+                "description": "Returns every stored version of the pack configuration, newest first. Each change creates a version\nwith a timestamp and the optional author and comment of the request.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "List pack configuration versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "List the versions of this product instead of the global configuration",
+                        "name": "sku",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ConfigHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/config/packs/recommend": {
             "post": {
-                "description": "Calculates the optimal pack combination for the requested quantity",
+                "description": "Suggests the pack sizes that minimize the total overage over a sample of historical order quantities,\nplus size_penalty for every size when given. The optimizer is a local search that scores each candidate\nset by solving every quantity of the sample, so the result is a good set rather than a proven optimum.\nAt most 10000 quantities are accepted. Nothing is stored; post the sizes to /config/packs to apply them.",
                 "consumes": [
                     "application/json"
                 ],
@@ -113,17 +272,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "config"
                 ],
-                "summary": "Calculate pack distribution",
+                "summary": "Recommend pack sizes",
                 "parameters": [
                     {
-                        "description": "Order quantity",
+                        "description": "Historical quantities and number of sizes",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/http.OrderRequest"
+                            "$ref": "#/definitions/http.RecommendRequest"
                         }
                     }
                 ],
@@ -131,7 +290,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/http.OrderResponse"
+                            "$ref": "#/definitions/packsolver.Recommendation"
                         }
                     },
                     "400": {
@@ -143,8 +302,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "504": {
+                        "description": "Gateway Timeout",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -154,70 +313,1189 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "http.OrderRequest": {
-            "type": "object",
-            "required": [
-                "quantity"
-            ],
-            "properties": {
-                "quantity": {
-                    "type": "integer"
-                }
-            }
         },
-        "http.OrderResponse": {
-            "type": "object",
-            "properties": {
-                "packs": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/packsolver.Pack"
+        "/config/packs/rollback/{n}": {
+            "post": {
+                "description": "Makes version n the current pack configuration again. The history is kept: the restored\nconfiguration is stored as a new version with rollback_of set to n. If-Match works as for POST /config/packs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Roll back the pack configuration",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Version to restore",
+                        "name": "n",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Roll back this product instead of the global configuration",
+                        "name": "sku",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Author and comment",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/http.RollbackRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version the rollback is based on",
+                        "name": "If-Match",
+                        "in": "header"
                     }
-                },
-                "total_items": {
-                    "type": "integer"
-                }
-            }
-        },
-        "http.PackConfigRequest": {
-            "type": "object",
-            "required": [
-                "pack_sizes"
-            ],
-            "properties": {
-                "pack_sizes": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/config.ConfigVersion"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version created by the rollback"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "http.PackConfigResponse": {
-            "type": "object",
-            "properties": {
-                "pack_sizes": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
+        "/config/packs/versions/{n}": {
+            "get": {
+                "description": "Returns one stored version of the pack configuration",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Get a pack configuration version",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Version number",
+                        "name": "n",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Read a version of this product instead of the global configuration",
+                        "name": "sku",
+                        "in": "query"
                     }
-                },
-                "success": {
-                    "type": "boolean"
-                }
-            }
-        },
-        "packsolver.Pack": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/config.ConfigVersion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/config/products": {
+            "get": {
+                "description": "Returns the SKUs that have their own pack configuration",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "List configured products",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ProductListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/config/products/{sku}/packs": {
+            "get": {
+                "description": "Returns the pack sizes, costs and packaging hierarchy configured for one SKU, with the config version in the ETag header",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Get the pack configuration of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product SKU",
+                        "name": "sku",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.PackConfigResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Config version of the product"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Sets the pack sizes (and optional costs and packaging) of one SKU, with the same rules as POST /config/packs.\nOrders naming the SKU are solved with this configuration instead of the global one.\nIf-Match \"0\" creates the product only if it does not exist yet.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Update the pack configuration of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product SKU",
+                        "name": "sku",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pack sizes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.PackConfigRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version the change is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.PackConfigResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version created by the change"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/order": {
+            "post": {
+                "description": "Calculates the optimal pack combination for the requested quantity.\nThe strategy can be chosen in the body or with the strategy query parameter (body wins).\nAn optional inventory map limits how many packs of each size may be used.\nWith objective=cost the cheapest distribution is returned, ties fall back to overage.\nexact, max_overage and max_overage_percent reject answers shipping too many extra items with 422.\nconstraints limit the packs per size (min, max) and in total (max_packs); they need the dp or dfs strategy.\nWhen a packaging hierarchy is configured the packs are also rolled up into it (packaging tree).\nWith a sku the pack configuration of that product is used (404 when it is not configured).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Calculate pack distribution",
+                "parameters": [
+                    {
+                        "description": "Order quantity",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.OrderRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Solving strategy (greedy, dp, dfs, bnb, smart)",
+                        "name": "strategy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of ranked alternative distributions to return (1-10)",
+                        "name": "alternatives",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/order/verify": {
+            "post": {
+                "description": "Checks that every proposed size is configured and that the packs cover the quantity, and compares\nthe proposal with the optimum (extra_items, extra_packs). An invalid proposal is still answered with 200\nand valid=false; 400 is reserved for malformed requests.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Verify a proposed pack distribution",
+                "parameters": [
+                    {
+                        "description": "Quantity and proposed packs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.VerifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/packsolver.Verification"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/batch": {
+            "post": {
+                "description": "Solves up to 1000 order lines in one call. The pack configuration is loaded once and the\nlines are solved in parallel by a bounded worker pool (BATCH_WORKERS, defaults to the CPU count).\nResults keep the input order; every line reports its own status and error.\nLines may name a sku to use the pack sizes of that product, e.g. {\"lines\": [{\"sku\": \"A\", \"quantity\": 10}]}.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Calculate pack distributions for many order lines",
+                "parameters": [
+                    {
+                        "description": "Order lines",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.BatchOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.BatchOrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "config.ConfigVersion": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "pack_costs": {
+                    "description": "unit cost per size, optional",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "pack_sizes": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "packaging": {
+                    "description": "cases, pallets, ..., optional",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packsolver.PackagingLevel"
+                    }
+                },
+                "rollback_of": {
+                    "description": "version this one restored, for rollbacks",
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "1 for the first change, then +1 for every change; 0 when never changed",
+                    "type": "integer"
+                }
+            }
+        },
+        "http.AnalysisResponse": {
+            "type": "object",
+            "properties": {
+                "frobenius": {
+                    "description": "Largest quantity that cannot be filled exactly (the Frobenius number), 0 when every\nquantity can and nil when there is no largest one because the GCD is above 1",
+                    "type": "integer"
+                },
+                "gcd": {
+                    "description": "every exactly fillable quantity is a multiple of it",
+                    "type": "integer"
+                },
+                "largest_unfillable_multiple": {
+                    "description": "Largest multiple of the GCD that cannot be filled exactly, 0 when there is none",
+                    "type": "integer"
+                },
+                "range": {
+                    "description": "set when from and to are given",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/packsolver.RangeAnalysis"
+                        }
+                    ]
+                },
+                "sizes": {
+                    "description": "unique, ascending",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "unfillable_multiples": {
+                    "description": "Number of multiples of the GCD that cannot be filled exactly",
+                    "type": "integer"
+                },
+                "worst_overage": {
+                    "description": "Largest overage the optimal distribution ships for any quantity, and the smallest\nquantity where it happens",
+                    "type": "integer"
+                },
+                "worst_overage_quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http.BatchOrderRequest": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.OrderRequest"
+                    }
+                },
+                "quantities": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "http.BatchOrderResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.BatchOrderResult"
+                    }
+                }
+            }
+        },
+        "http.BatchOrderResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "same body as an error from POST /order",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "order": {
+                    "description": "set when Status is 200",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/http.OrderResponse"
+                        }
+                    ]
+                },
+                "status": {
+                    "description": "HTTP status the line would get from POST /order",
+                    "type": "integer"
+                }
+            }
+        },
+        "http.ConfigHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "versions": {
+                    "description": "newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.ConfigVersion"
+                    }
+                }
+            }
+        },
+        "http.ConfigWarning": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "redundant_multiple or redundant_combination",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "replacement": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packsolver.PackResult"
+                    }
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http.OrderRequest": {
+            "type": "object",
+            "required": [
+                "quantity"
+            ],
+            "properties": {
+                "alternatives": {
+                    "description": "number of ranked alternatives to return (1-10)",
+                    "type": "integer"
+                },
+                "constraints": {
+                    "description": "Per-size minimum/maximum counts and a cap on the total packs; dp and dfs only",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/packsolver.Constraints"
+                        }
+                    ]
+                },
+                "exact": {
+                    "description": "Overage tolerance; when several are set the strictest one applies",
+                    "type": "boolean"
+                },
+                "inventory": {
+                    "description": "available packs per size; unlisted sizes are unlimited",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "max_overage": {
+                    "description": "at most this many extra items",
+                    "type": "integer"
+                },
+                "max_overage_percent": {
+                    "description": "at most this percentage of the quantity",
+                    "type": "number"
+                },
+                "objective": {
+                    "description": "items (default) or cost",
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "description": "product whose pack configuration applies; global when empty",
+                    "type": "string"
+                },
+                "strategy": {
+                    "description": "greedy, dp, dfs, bnb or smart (default)",
+                    "type": "string"
+                }
+            }
+        },
+        "http.OrderResponse": {
+            "type": "object",
+            "properties": {
+                "alternatives": {
+                    "description": "best distributions ranked by items, then packs",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packsolver.Solution"
+                    }
+                },
+                "config_version": {
+                    "description": "version of the pack configuration the order was solved with",
+                    "type": "integer"
+                },
+                "cost": {
+                    "description": "packaging cost, when costs are configured",
+                    "type": "number"
+                },
+                "packaging": {
+                    "description": "packs rolled up into cases, pallets, ...; when configured",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packsolver.PackageNode"
+                    }
+                },
+                "packs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packsolver.PackResult"
+                    }
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "strategy": {
+                    "description": "strategy that produced the answer",
+                    "type": "string"
+                },
+                "total_items": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http.PackConfigRequest": {
+            "type": "object",
+            "required": [
+                "pack_sizes"
+            ],
+            "properties": {
+                "author": {
+                    "description": "recorded in the config history",
+                    "type": "string"
+                },
+                "comment": {
+                    "description": "recorded in the config history",
+                    "type": "string"
+                },
+                "items_only": {
+                    "description": "only the items shipped matter: warn about sizes that smaller sizes can replace",
+                    "type": "boolean"
+                },
+                "pack_costs": {
+                    "description": "unit cost per size; kept unchanged when omitted, {} removes them",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "pack_sizes": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "packaging": {
+                    "description": "cases, pallets, ...; kept unchanged when omitted, [] removes it",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packsolver.PackagingLevel"
+                    }
+                },
+                "strict": {
+                    "description": "reject those sizes instead of warning",
+                    "type": "boolean"
+                }
+            }
+        },
+        "http.PackConfigResponse": {
+            "type": "object",
+            "properties": {
+                "pack_costs": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "pack_sizes": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "packaging": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packsolver.PackagingLevel"
+                    }
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "version": {
+                    "description": "config version, 0 when never changed",
+                    "type": "integer"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.ConfigWarning"
+                    }
+                }
+            }
+        },
+        "http.ProductListResponse": {
+            "type": "object",
+            "properties": {
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "http.RecommendRequest": {
+            "type": "object",
+            "required": [
+                "count",
+                "quantities"
+            ],
+            "properties": {
+                "candidates": {
+                    "description": "sizes to choose from; the most frequent quantities by default",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "count": {
+                    "description": "number of sizes to recommend",
+                    "type": "integer"
+                },
+                "quantities": {
+                    "description": "historical order quantities",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "size_penalty": {
+                    "description": "overage items one extra size must save; Count becomes an upper bound",
+                    "type": "number"
+                },
+                "strategy": {
+                    "description": "solver scoring the candidate sets; bnb by default",
+                    "type": "string"
+                }
+            }
+        },
+        "http.RollbackRequest": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "comment": {
+                    "description": "defaults to \"rollback to version n\"",
+                    "type": "string"
+                }
+            }
+        },
+        "http.VerifyRequest": {
+            "type": "object",
+            "required": [
+                "quantity"
+            ],
+            "properties": {
+                "packs": {
+                    "description": "proposed distribution",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packsolver.PackResult"
+                    }
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "description": "product whose pack configuration applies; global when empty",
+                    "type": "string"
+                }
+            }
+        },
+        "packsolver.Constraints": {
+            "type": "object",
+            "properties": {
+                "max": {
+                    "description": "maximum number of packs per size",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "max_packs": {
+                    "description": "maximum total number of packs, 0 for no cap",
+                    "type": "integer"
+                },
+                "min": {
+                    "description": "minimum number of packs per size",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "packsolver.PackResult": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "how many times this pack is used",
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "size of the pack",
+                    "type": "integer"
+                }
+            }
+        },
+        "packsolver.PackageNode": {
+            "type": "object",
+            "properties": {
+                "contents": {
+                    "description": "what one unit holds",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packsolver.PackageNode"
+                    }
+                },
+                "count": {
+                    "description": "number of identical units in the group",
+                    "type": "integer"
+                },
+                "items": {
+                    "description": "items held by one unit",
+                    "type": "integer"
+                },
+                "level": {
+                    "description": "name of the packaging level, or \"pack\"",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "items per pack, pack level only",
+                    "type": "integer"
+                }
+            }
+        },
+        "packsolver.PackagingLevel": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "description": "further levels: units of the level below per unit",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "per_pack": {
+                    "description": "first level only: packs per unit, by pack size",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "packsolver.RangeAnalysis": {
+            "type": "object",
+            "properties": {
+                "exact_quantities": {
+                    "description": "quantities filled without overage",
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "mean_overage": {
+                    "type": "number"
+                },
+                "to": {
+                    "type": "integer"
+                },
+                "worst_overage": {
+                    "type": "integer"
+                },
+                "worst_overage_quantity": {
+                    "description": "smallest quantity with the worst overage",
+                    "type": "integer"
+                }
+            }
+        },
+        "packsolver.Recommendation": {
+            "type": "object",
+            "properties": {
+                "exact_orders": {
+                    "description": "orders of the sample filled without overage",
+                    "type": "integer"
+                },
+                "mean_overage": {
+                    "type": "number"
+                },
+                "score": {
+                    "description": "TotalOverage + SizePenalty × len(Sizes), the value minimized",
+                    "type": "number"
+                },
+                "sizes": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "total_overage": {
+                    "description": "items shipped beyond the quantities, summed over the sample",
+                    "type": "integer"
+                }
+            }
+        },
+        "packsolver.Solution": {
+            "type": "object",
+            "properties": {
+                "packs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packsolver.PackResult"
+                    }
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_packs": {
+                    "type": "integer"
+                }
+            }
+        },
+        "packsolver.Verification": {
+            "type": "object",
+            "properties": {
+                "extra_items": {
+                    "description": "TotalItems - Optimal.TotalItems",
+                    "type": "integer"
+                },
+                "extra_packs": {
+                    "description": "TotalPacks - Optimal.TotalPacks, negative when fewer packs are used",
+                    "type": "integer"
+                },
+                "is_optimal": {
+                    "description": "valid and as good as the optimum",
+                    "type": "boolean"
+                },
+                "optimal": {
+                    "description": "best distribution, as returned by SolvePackDistribution",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/packsolver.Solution"
+                        }
+                    ]
+                },
+                "shortfall": {
+                    "description": "items missing to cover the quantity",
+                    "type": "integer"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_packs": {
+                    "type": "integer"
+                },
+                "unknown_sizes": {
+                    "description": "proposed sizes that are not configured",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "valid": {
+                    "description": "every size is configured and the packs cover the quantity",
+                    "type": "boolean"
                 }
             }
         }
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long CachedStore serves a configuration without reading it again
+// when CONFIG_CACHE_TTL is not set. Changes are normally seen at once through the change
+// feed; the TTL only bounds how long a lost notification can go unnoticed.
+const DefaultCacheTTL = time.Minute
+
+// changeFeed is implemented by stores shared between instances that announce changes.
+type changeFeed interface {
+	// watchChanges calls changed with the SKU of every change until ctx is done, and
+	// resync whenever changes may have been missed.
+	watchChanges(ctx context.Context, changed func(sku string), resync func())
+}
+
+// CachedStore keeps the current configuration of every SKU in memory, so that orders do
+// not reach the underlying store. Entries are read again after the TTL, dropped when the
+// configuration is changed through this store, and, when the store has a change feed
+// (Redis pub/sub), dropped as soon as any other instance changes it. Versions and
+// products are always read from the underlying store.
+type CachedStore struct {
+	store ConfigStore
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	gen     uint64 // bumped by every invalidation, so a read that raced one is not cached
+
+	stop context.CancelFunc
+	done chan struct{}
+}
+
+// cacheEntry is a cached configuration, or ErrUnknownProduct for a product that has none.
+type cacheEntry struct {
+	cfg     *ConfigVersion
+	err     error
+	expires time.Time
+}
+
+// NewCachedStore caches the current configurations of store for ttl, which must be positive.
+// Close stops watching for changes and closes store.
+func NewCachedStore(store ConfigStore, ttl time.Duration) *CachedStore {
+	ctx, stop := context.WithCancel(context.Background())
+	s := &CachedStore{
+		store:   store,
+		ttl:     ttl,
+		entries: map[string]cacheEntry{},
+		stop:    stop,
+		done:    make(chan struct{}),
+	}
+	go func() {
+		defer close(s.done)
+		if feed, ok := store.(changeFeed); ok {
+			feed.watchChanges(ctx, s.Invalidate, s.InvalidateAll)
+		}
+	}()
+	return s
+}
+
+// Close stops watching for changes and closes the underlying store when it can be closed.
+func (s *CachedStore) Close() error {
+	s.stop()
+	<-s.done
+	if closer, ok := s.store.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// GetPackConfig returns a copy of the cached configuration of the SKU, reading it from the
+// underlying store when it is missing or expired.
+func (s *CachedStore) GetPackConfig(ctx context.Context, sku string) (*ConfigVersion, error) {
+	s.mu.Lock()
+	entry, ok := s.entries[sku]
+	gen := s.gen
+	s.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.get()
+	}
+
+	cfg, err := s.store.GetPackConfig(ctx, sku)
+	if err != nil && !errors.Is(err, ErrUnknownProduct) {
+		return nil, err // not cached, the next order tries again
+	}
+	entry = cacheEntry{cfg: cfg, err: err, expires: time.Now().Add(s.ttl)}
+
+	s.mu.Lock()
+	if s.gen == gen {
+		s.entries[sku] = entry
+	}
+	s.mu.Unlock()
+	return entry.get()
+}
+
+// SetPackConfig stores the change in the underlying store and drops the cached configuration.
+func (s *CachedStore) SetPackConfig(ctx context.Context, sku string, cfg PackConfig, change Change) (*ConfigVersion, error) {
+	saved, err := s.store.SetPackConfig(ctx, sku, cfg, change)
+	s.Invalidate(sku) // also on errors: the change may have been stored before the error
+	return saved, err
+}
+
+// ListVersions returns every version of the SKU from the underlying store, newest first.
+func (s *CachedStore) ListVersions(ctx context.Context, sku string) ([]ConfigVersion, error) {
+	return s.store.ListVersions(ctx, sku)
+}
+
+// GetVersion returns version n of the SKU from the underlying store.
+func (s *CachedStore) GetVersion(ctx context.Context, sku string, n int) (*ConfigVersion, error) {
+	return s.store.GetVersion(ctx, sku, n)
+}
+
+// ListProducts returns the SKUs of all configured products from the underlying store.
+func (s *CachedStore) ListProducts(ctx context.Context) ([]string, error) {
+	return s.store.ListProducts(ctx)
+}
+
+// Invalidate drops the cached configuration of the SKU.
+func (s *CachedStore) Invalidate(sku string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, sku)
+	s.gen++
+}
+
+// InvalidateAll drops every cached configuration.
+func (s *CachedStore) InvalidateAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.entries)
+	s.gen++
+}
+
+// get returns a copy of the cached configuration, so callers never share it.
+func (e cacheEntry) get() (*ConfigVersion, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	cfg := e.cfg.clone()
+	return &cfg, nil
+}
@@ -0,0 +1,131 @@
+package config_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/rapido-liebre/pack_solver/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// newCachedRedisStore returns a cached store on miniredis, a second instance sharing the
+// same Redis, and the miniredis server.
+func newCachedRedisStore(t *testing.T) (*miniredis.Miniredis, *config.CachedStore, *config.RedisStore) {
+	s, store := newMockRedisStore(t)
+	cached := config.NewCachedStore(store, time.Hour)
+	t.Cleanup(func() { _ = cached.Close() })
+
+	other, err := config.NewRedisStore(s.Addr())
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = other.Close() })
+
+	// wait for the change feed, so no test change is published before it listens
+	assert.Eventually(t, func() bool { return s.PubSubNumSub(config.PackChangesChannel)[config.PackChangesChannel] == 1 },
+		time.Second, 5*time.Millisecond)
+	return s, cached, other
+}
+
+func TestCachedStoreServesFromMemory(t *testing.T) {
+	ctx := context.Background()
+	s, cached, other := newCachedRedisStore(t)
+	set(t, other, "", config.PackConfig{Sizes: []int{250, 500}})
+
+	cfg, err := cached.GetPackConfig(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{250, 500}, cfg.Sizes)
+
+	// orders no longer reach Redis, once the notification of the change above is handled
+	assert.Eventually(t, func() bool {
+		commands := s.CommandCount()
+		for range 10 {
+			cfg, err = cached.GetPackConfig(ctx, "")
+			assert.NoError(t, err)
+			assert.Equal(t, 1, cfg.Version)
+		}
+		return s.CommandCount() == commands
+	}, time.Second, 5*time.Millisecond)
+
+	// callers get their own copy
+	cfg.Sizes[0] = 1
+	cfg, _ = cached.GetPackConfig(ctx, "")
+	assert.Equal(t, []int{250, 500}, cfg.Sizes)
+}
+
+func TestCachedStoreInvalidatesOnChange(t *testing.T) {
+	ctx := context.Background()
+	_, cached, other := newCachedRedisStore(t)
+
+	// changes through the cache are visible at once
+	set(t, cached, "", config.PackConfig{Sizes: []int{250}})
+	cfg, err := cached.GetPackConfig(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{250}, cfg.Sizes)
+
+	// changes of another instance arrive through pub/sub
+	set(t, other, "", config.PackConfig{Sizes: []int{500}})
+	assert.Eventually(t, func() bool {
+		cfg, err := cached.GetPackConfig(ctx, "")
+		return err == nil && cfg.Version == 2
+	}, time.Second, 5*time.Millisecond)
+
+	// unknown products are cached as well, until they are configured
+	_, err = cached.GetPackConfig(ctx, "SKU-1")
+	assert.ErrorIs(t, err, config.ErrUnknownProduct)
+	set(t, other, "SKU-1", config.PackConfig{Sizes: []int{6, 12}})
+	assert.Eventually(t, func() bool {
+		cfg, err := cached.GetPackConfig(ctx, "SKU-1")
+		return err == nil && assert.ObjectsAreEqual([]int{6, 12}, cfg.Sizes)
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestCachedStoreTTL(t *testing.T) {
+	ctx := context.Background()
+	store := config.NewMemoryStore()
+	set(t, store, "", config.PackConfig{Sizes: []int{250}})
+	cached := config.NewCachedStore(store, 50*time.Millisecond)
+	t.Cleanup(func() { _ = cached.Close() })
+
+	cfg, err := cached.GetPackConfig(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{250}, cfg.Sizes)
+
+	// a change the cache is not told about shows up once the entry expires
+	set(t, store, "", config.PackConfig{Sizes: []int{500}})
+	cfg, _ = cached.GetPackConfig(ctx, "")
+	assert.Equal(t, []int{250}, cfg.Sizes)
+	assert.Eventually(t, func() bool {
+		cfg, err := cached.GetPackConfig(ctx, "")
+		return err == nil && cfg.Version == 2
+	}, time.Second, 10*time.Millisecond)
+
+	set(t, store, "", config.PackConfig{Sizes: []int{1000}})
+	cached.InvalidateAll()
+	cfg, _ = cached.GetPackConfig(ctx, "")
+	assert.Equal(t, []int{1000}, cfg.Sizes)
+}
+
+func TestCachedStoreKeepsConfigStoreContract(t *testing.T) {
+	ctx := context.Background()
+	cached := config.NewCachedStore(config.NewMemoryStore(), time.Hour)
+	t.Cleanup(func() { _ = cached.Close() })
+
+	set(t, cached, "SKU-1", config.PackConfig{Sizes: []int{6}})
+	set(t, cached, "SKU-1", config.PackConfig{Sizes: []int{12}})
+
+	one := 1
+	_, err := cached.SetPackConfig(ctx, "SKU-1", config.PackConfig{Sizes: []int{24}}, config.Change{IfVersion: &one})
+	var conflict *config.VersionConflictError
+	assert.ErrorAs(t, err, &conflict)
+
+	versions, err := cached.ListVersions(ctx, "SKU-1")
+	assert.NoError(t, err)
+	assert.Len(t, versions, 2)
+	v, err := cached.GetVersion(ctx, "SKU-1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{6}, v.Sizes)
+	products, err := cached.ListProducts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SKU-1"}, products)
+}
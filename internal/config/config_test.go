@@ -1,48 +1,85 @@
 package config_test
 
 import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
 	"github.com/rapido-liebre/pack_solver/internal/config"
 	"github.com/stretchr/testify/assert"
-	"os"
+	"path/filepath"
 	"testing"
 )
 
 func TestSetAndGetPackSizes(t *testing.T) {
-	err := os.Setenv("REDIS_ADDR", "localhost:6379")
-	assert.NoError(t, err)
-
-	err = config.InitRedis()
-	assert.NoError(t, err)
+	_, store := newMockRedisStore(t)
 
 	sizes := []int{100, 200, 300}
-	err = config.SetPackSizes(sizes)
+	_, err := store.SetPackConfig(context.Background(), "", config.PackConfig{Sizes: sizes}, config.Change{})
 	assert.NoError(t, err)
 
-	result, err := config.GetPackSizes()
+	result, err := store.GetPackConfig(context.Background(), "")
 	assert.NoError(t, err)
-	assert.ElementsMatch(t, sizes, result)
+	assert.ElementsMatch(t, sizes, result.Sizes)
 }
 
-func TestSetPackSizesInvalidRedis(t *testing.T) {
-	err := os.Setenv("REDIS_ADDR", "localhost:6380")
-	assert.NoError(t, err)
+func TestGetPackSizesEmpty(t *testing.T) {
+	_, redisStore := newMockRedisStore(t)
+	store := config.WithDefaults(redisStore, config.PackConfig{Sizes: config.DefaultPackSizes})
 
-	err = config.InitRedis()
-	assert.Error(t, err)
+	cfg, err := store.GetPackConfig(context.Background(), "")
+	assert.NoError(t, err) // returns default config
+	assert.Equal(t, config.DefaultPackSizes, cfg.Sizes)
+}
 
+func TestNewRedisStoreInvalidRedis(t *testing.T) {
 	// simulate unavailable redis
-	sizes := []int{100, 200}
-	err = config.SetPackSizes(sizes)
+	_, err := config.NewRedisStore("localhost:6380")
+	assert.Error(t, err)
+
+	_, err = config.NewRedisStore("")
 	assert.Error(t, err)
 }
 
-func TestGetPackSizesEmpty(t *testing.T) {
-	err := os.Setenv("REDIS_ADDR", "localhost:6379")
+func TestNewStoreFromEnv(t *testing.T) {
+	t.Setenv("CONFIG_STORE", "memory")
+	store, err := config.NewStoreFromEnv()
 	assert.NoError(t, err)
+	assert.IsType(t, &config.MemoryStore{}, store)
 
-	err = config.InitRedis()
+	t.Setenv("CONFIG_STORE", "file")
+	t.Setenv("CONFIG_FILE", "")
+	_, err = config.NewStoreFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "packs.yaml"))
+	store, err = config.NewStoreFromEnv()
 	assert.NoError(t, err)
+	assert.IsType(t, &config.FileStore{}, store)
 
-	_, err = config.GetPackSizes()
-	assert.NoError(t, err) // returns default config
+	t.Setenv("CONFIG_STORE", "redis")
+	t.Setenv("REDIS_ADDR", "localhost:6380")
+	_, err = config.NewStoreFromEnv()
+	assert.Error(t, err)
+
+	mr := miniredis.RunT(t)
+	t.Setenv("REDIS_ADDR", mr.Addr())
+	cached, err := config.NewStoreFromEnv()
+	assert.NoError(t, err)
+	if assert.IsType(t, &config.CachedStore{}, cached) {
+		assert.NoError(t, cached.(*config.CachedStore).Close())
+	}
+
+	t.Setenv("CONFIG_CACHE_TTL", "0")
+	uncached, err := config.NewStoreFromEnv()
+	assert.NoError(t, err)
+	if assert.IsType(t, &config.RedisStore{}, uncached) {
+		assert.NoError(t, uncached.(*config.RedisStore).Close())
+	}
+
+	t.Setenv("CONFIG_CACHE_TTL", "soon")
+	_, err = config.NewStoreFromEnv()
+	assert.ErrorContains(t, err, "invalid CONFIG_CACHE_TTL")
+
+	t.Setenv("CONFIG_STORE", "etcd")
+	_, err = config.NewStoreFromEnv()
+	assert.ErrorContains(t, err, "unknown CONFIG_STORE")
 }
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+)
+
+// DefaultPackSizes are the global pack sizes used when neither DEFAULT_PACK_SIZES nor
+// DEFAULT_PACK_FILE gives any.
+var DefaultPackSizes = []int{250, 500, 1000, 2000, 5000}
+
+// seedAuthor is recorded as the author of the version that Seed writes.
+const seedAuthor = "pack-solver"
+
+// DefaultsFromEnv returns the default global configuration, from the first of:
+//   - DEFAULT_PACK_SIZES, a comma-separated list such as "250,500,1000"
+//   - DEFAULT_PACK_FILE, a JSON or YAML file with pack_sizes and optional pack_costs and
+//     packaging, the layout of the top level of a configuration file
+//   - DefaultPackSizes
+func DefaultsFromEnv() (PackConfig, error) {
+	if v := os.Getenv("DEFAULT_PACK_SIZES"); v != "" {
+		var cfg PackConfig
+		for _, field := range strings.Split(v, ",") {
+			size, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil {
+				return PackConfig{}, fmt.Errorf("invalid DEFAULT_PACK_SIZES %q: %w", v, err)
+			}
+			cfg.Sizes = append(cfg.Sizes, size)
+		}
+		if err := cfg.validate(); err != nil {
+			return PackConfig{}, fmt.Errorf("invalid DEFAULT_PACK_SIZES %q: %w", v, err)
+		}
+		return cfg.normalized(), nil
+	}
+
+	if path := os.Getenv("DEFAULT_PACK_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return PackConfig{}, err
+		}
+		if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+			if data, err = yamlToJSON(data); err != nil {
+				return PackConfig{}, fmt.Errorf("invalid default pack file %s: %w", path, err)
+			}
+		}
+		var cfg PackConfig
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return PackConfig{}, fmt.Errorf("invalid default pack file %s: %w", path, err)
+		}
+		if err := cfg.validate(); err != nil {
+			return PackConfig{}, fmt.Errorf("invalid default pack file %s: %w", path, err)
+		}
+		return cfg.normalized(), nil
+	}
+
+	return PackConfig{Sizes: slices.Clone(DefaultPackSizes)}, nil
+}
+
+// Seed stores defaults as the global configuration when it has never been set or has no
+// sizes, and returns the version it created; nil when the store already had sizes. It is
+// safe to run from several instances at once: only one of them writes the defaults.
+func Seed(ctx context.Context, store ConfigStore, defaults PackConfig) (*ConfigVersion, error) {
+	current, err := store.GetPackConfig(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(current.Sizes) > 0 {
+		return nil, nil
+	}
+
+	saved, err := store.SetPackConfig(ctx, "", defaults, Change{
+		Author:    seedAuthor,
+		Comment:   "default pack sizes",
+		IfVersion: &current.Version,
+	})
+	var conflict *VersionConflictError
+	if errors.As(err, &conflict) {
+		return nil, nil // another instance seeded or changed it first
+	}
+	return saved, err
+}
+
+// FallbackStore serves default global pack sizes while the store has none, e.g. until Seed
+// has written them or after the configuration was deleted by hand. Products, versions and
+// changes are left to the underlying store.
+type FallbackStore struct {
+	ConfigStore
+	defaults PackConfig
+}
+
+// WithDefaults returns store serving defaults as the global configuration while it has no sizes.
+func WithDefaults(store ConfigStore, defaults PackConfig) *FallbackStore {
+	return &FallbackStore{ConfigStore: store, defaults: defaults.clone()}
+}
+
+// GetPackConfig returns the configuration of the SKU, or for the global configuration
+// the defaults at its current version when it has no sizes.
+func (s *FallbackStore) GetPackConfig(ctx context.Context, sku string) (*ConfigVersion, error) {
+	cfg, err := s.ConfigStore.GetPackConfig(ctx, sku)
+	if err != nil || sku != "" || len(cfg.Sizes) > 0 {
+		return cfg, err
+	}
+	cfg.PackConfig = s.defaults.clone()
+	return cfg, nil
+}
+
+// Close closes the underlying store when it can be closed.
+func (s *FallbackStore) Close() error {
+	if closer, ok := s.ConfigStore.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// validate checks a configuration that did not come through the API: positive sizes, a
+// non-negative cost for every size when costs are given, and a valid packaging hierarchy.
+func (c PackConfig) validate() error {
+	if len(c.Sizes) == 0 {
+		return errors.New("no pack sizes")
+	}
+	for _, size := range c.Sizes {
+		if size <= 0 {
+			return errors.New("pack sizes must be > 0")
+		}
+	}
+	if c.Costs != nil {
+		for size, cost := range c.Costs {
+			if cost < 0 {
+				return errors.New("pack costs must be >= 0")
+			}
+			if !slices.Contains(c.Sizes, size) {
+				return errors.New("pack_costs contains a size that is not in pack_sizes")
+			}
+		}
+		for _, size := range c.Sizes {
+			if _, ok := c.Costs[size]; !ok {
+				return errors.New("pack_costs must contain a cost for every pack size")
+			}
+		}
+	}
+	if len(c.Packaging) > 0 {
+		return packsolver.ValidatePackaging(c.Packaging, c.Sizes)
+	}
+	return nil
+}
+
+// normalized returns the configuration with its sizes sorted and without duplicates.
+func (c PackConfig) normalized() PackConfig {
+	out := c.clone()
+	slices.Sort(out.Sizes)
+	out.Sizes = slices.Compact(out.Sizes)
+	return out
+}
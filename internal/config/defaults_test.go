@@ -0,0 +1,131 @@
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultsFromEnv(t *testing.T) {
+	t.Setenv("DEFAULT_PACK_SIZES", "")
+	t.Setenv("DEFAULT_PACK_FILE", "")
+	defaults, err := config.DefaultsFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, config.DefaultPackSizes, defaults.Sizes)
+
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "defaults.yaml")
+	assert.NoError(t, os.WriteFile(yamlPath, []byte("pack_sizes: [500, 250]\npack_costs: {250: 0.5, 500: 0.8}\n"), 0o644))
+	t.Setenv("DEFAULT_PACK_FILE", yamlPath)
+	defaults, err = config.DefaultsFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{250, 500}, defaults.Sizes)
+	assert.Equal(t, map[int]float64{250: 0.5, 500: 0.8}, defaults.Costs)
+
+	// the env var wins over the file
+	t.Setenv("DEFAULT_PACK_SIZES", "1000, 250,500,250")
+	defaults, err = config.DefaultsFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{250, 500, 1000}, defaults.Sizes)
+
+	for _, v := range []string{"250,abc", "250,0", ","} {
+		t.Setenv("DEFAULT_PACK_SIZES", v)
+		_, err = config.DefaultsFromEnv()
+		assert.ErrorContains(t, err, "invalid DEFAULT_PACK_SIZES", v)
+	}
+
+	t.Setenv("DEFAULT_PACK_SIZES", "")
+	jsonPath := filepath.Join(dir, "defaults.json")
+	assert.NoError(t, os.WriteFile(jsonPath, []byte(`{"pack_sizes": [250], "pack_costs": {"500": 1}}`), 0o644))
+	t.Setenv("DEFAULT_PACK_FILE", jsonPath)
+	_, err = config.DefaultsFromEnv()
+	assert.ErrorContains(t, err, "invalid default pack file")
+
+	t.Setenv("DEFAULT_PACK_FILE", filepath.Join(dir, "missing.json"))
+	_, err = config.DefaultsFromEnv()
+	assert.Error(t, err)
+}
+
+func TestSeed(t *testing.T) {
+	ctx := context.Background()
+	defaults := config.PackConfig{Sizes: []int{250, 500, 1000}}
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			seeded, err := config.Seed(ctx, store, defaults)
+			assert.NoError(t, err)
+			if assert.NotNil(t, seeded) {
+				assert.Equal(t, 1, seeded.Version)
+				assert.Equal(t, "pack-solver", seeded.Author)
+			}
+			current, err := store.GetPackConfig(ctx, "")
+			assert.NoError(t, err)
+			assert.Equal(t, []int{250, 500, 1000}, current.Sizes)
+
+			// configured sizes are never replaced
+			set(t, store, "", config.PackConfig{Sizes: []int{300}})
+			seeded, err = config.Seed(ctx, store, defaults)
+			assert.NoError(t, err)
+			assert.Nil(t, seeded)
+			current, err = store.GetPackConfig(ctx, "")
+			assert.NoError(t, err)
+			assert.Equal(t, []int{300}, current.Sizes)
+		})
+	}
+}
+
+func TestSeedFromSeveralInstances(t *testing.T) {
+	s, shared := newMockRedisStore(t)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		store, err := config.NewRedisStore(s.Addr())
+		assert.NoError(t, err)
+		t.Cleanup(func() { _ = store.Close() })
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := config.Seed(context.Background(), store, config.PackConfig{Sizes: []int{250}})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// only one instance wrote the defaults
+	versions, err := shared.ListVersions(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Len(t, versions, 1)
+}
+
+func TestFallbackStore(t *testing.T) {
+	ctx := context.Background()
+	s, redisStore := newMockRedisStore(t)
+	store := config.WithDefaults(redisStore, config.PackConfig{Sizes: []int{250, 500}})
+
+	// served until the store has sizes of its own
+	cfg, err := store.GetPackConfig(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{250, 500}, cfg.Sizes)
+	assert.Equal(t, 0, cfg.Version)
+
+	set(t, store, "", config.PackConfig{Sizes: []int{300}})
+	cfg, err = store.GetPackConfig(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{300}, cfg.Sizes)
+	assert.Equal(t, 1, cfg.Version)
+
+	// and again when the key is deleted by hand
+	s.Del(config.PackSizesKey)
+	cfg, err = store.GetPackConfig(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{250, 500}, cfg.Sizes)
+	assert.Equal(t, 1, cfg.Version)
+
+	// products have no fallback
+	_, err = store.GetPackConfig(ctx, "SKU-1")
+	assert.ErrorIs(t, err, config.ErrUnknownProduct)
+}
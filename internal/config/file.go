@@ -0,0 +1,166 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fileDocument is the layout of a configuration file: the global scope at the top level
+// and the products by SKU, each with its current configuration and history, e.g.
+//
+//	{"pack_sizes": [250, 500], "products": {"SKU-1": {"pack_sizes": [6, 12]}}}
+type fileDocument struct {
+	scope
+	Products map[string]*scope `json:"products,omitempty"`
+}
+
+// FileStore keeps the configuration in a JSON or YAML file, chosen by the extension
+// (.yaml or .yml for YAML). The file is read once when the store is created and
+// rewritten on every change; edits made to it while the service runs are not picked up.
+type FileStore struct {
+	mu   sync.Mutex // serializes changes so the file matches the memory
+	path string
+	yaml bool
+	mem  *MemoryStore
+}
+
+// NewFileStore loads the configuration from path. A missing file yields an empty store
+// and is created on the first change.
+func NewFileStore(path string) (*FileStore, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	s := &FileStore{path: path, yaml: ext == ".yaml" || ext == ".yml", mem: NewMemoryStore()}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var doc fileDocument
+	if s.yaml {
+		data, err = yamlToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	s.mem.load(doc)
+	return s, nil
+}
+
+// GetPackConfig returns a copy of the current configuration of the SKU.
+func (s *FileStore) GetPackConfig(ctx context.Context, sku string) (*ConfigVersion, error) {
+	return s.mem.GetPackConfig(ctx, sku)
+}
+
+// SetPackConfig stores cfg as the next version of the SKU and rewrites the file. When the
+// file cannot be written the previous state is kept.
+func (s *FileStore) SetPackConfig(ctx context.Context, sku string, cfg PackConfig, change Change) (*ConfigVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.mem.document()
+	saved, err := s.mem.SetPackConfig(ctx, sku, cfg, change)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.write(s.mem.document()); err != nil {
+		s.mem.load(previous)
+		return nil, err
+	}
+	return saved, nil
+}
+
+// ListVersions returns copies of every version of the SKU, newest first.
+func (s *FileStore) ListVersions(ctx context.Context, sku string) ([]ConfigVersion, error) {
+	return s.mem.ListVersions(ctx, sku)
+}
+
+// GetVersion returns a copy of version n of the SKU.
+func (s *FileStore) GetVersion(ctx context.Context, sku string, n int) (*ConfigVersion, error) {
+	return s.mem.GetVersion(ctx, sku, n)
+}
+
+// ListProducts returns the SKUs of all configured products, sorted.
+func (s *FileStore) ListProducts(ctx context.Context) ([]string, error) {
+	return s.mem.ListProducts(ctx)
+}
+
+// write replaces the file through a temporary file in the same directory, so readers
+// never see a partially written configuration.
+func (s *FileStore) write(doc fileDocument) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if s.yaml {
+		var v any
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		if data, err = yaml.Marshal(v); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), "."+filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// yamlToJSON converts a YAML document to JSON, so both formats share the JSON field names.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var v any
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonCompatible(v))
+}
+
+// jsonCompatible turns the map[any]any that YAML produces for non-string keys, such as
+// pack sizes in pack_costs, into map[string]any.
+func jsonCompatible(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = jsonCompatible(item)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, item := range v {
+			m[fmt.Sprint(k)] = jsonCompatible(item)
+		}
+		return m
+	case []any:
+		for i, item := range v {
+			v[i] = jsonCompatible(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
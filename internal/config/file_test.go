@@ -0,0 +1,101 @@
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/config"
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStorePersists(t *testing.T) {
+	ctx := context.Background()
+	for _, name := range []string{"packs.json", "packs.yaml", "packs.yml"} {
+		path := filepath.Join(t.TempDir(), name)
+		store, err := config.NewFileStore(path)
+		assert.NoError(t, err)
+
+		global := config.PackConfig{
+			Sizes:     []int{250, 500},
+			Costs:     map[int]float64{250: 0.5, 500: 0.8},
+			Packaging: []packsolver.PackagingLevel{{Name: "case", PerPack: map[int]int{250: 20, 500: 10}}, {Name: "pallet", Capacity: 40}},
+		}
+		set(t, store, "", global)
+		set(t, store, "SKU-1", config.PackConfig{Sizes: []int{6, 12}})
+
+		// a new store reads what the first one wrote
+		reopened, err := config.NewFileStore(path)
+		assert.NoError(t, err, name)
+		cfg, err := reopened.GetPackConfig(ctx, "")
+		assert.NoError(t, err)
+		assert.Equal(t, global, cfg.PackConfig, name)
+		skus, err := reopened.ListProducts(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"SKU-1"}, skus, name)
+
+		// no temporary files are left behind
+		entries, err := os.ReadDir(filepath.Dir(path))
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1, name)
+	}
+}
+
+func TestFileStoreReadsHandWrittenYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "packs.yaml")
+	data := `
+pack_sizes: [250, 500, 1000]
+pack_costs:
+  250: 0.5
+  500: 0.8
+  1000: 1.2
+products:
+  SKU-1:
+    pack_sizes: [6, 12]
+    packaging:
+      - name: case
+        per_pack: {6: 4, 12: 2}
+`
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+
+	store, err := config.NewFileStore(path)
+	assert.NoError(t, err)
+
+	cfg, err := store.GetPackConfig(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{250, 500, 1000}, cfg.Sizes)
+	assert.Equal(t, map[int]float64{250: 0.5, 500: 0.8, 1000: 1.2}, cfg.Costs)
+
+	cfg, err = store.GetPackConfig(context.Background(), "SKU-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []packsolver.PackagingLevel{{Name: "case", PerPack: map[int]int{6: 4, 12: 2}}}, cfg.Packaging)
+}
+
+func TestFileStoreInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"packs.json": `{"pack_sizes": [250,`,
+		"packs.yaml": "pack_sizes: [250\n",
+	} {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+		_, err := config.NewFileStore(path)
+		assert.ErrorContains(t, err, "invalid config file", name)
+	}
+}
+
+func TestFileStoreKeepsConfigWhenWriteFails(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := config.NewFileStore(filepath.Join(dir, "missing", "packs.json"))
+	assert.NoError(t, err)
+
+	_, err = store.SetPackConfig(ctx, "", config.PackConfig{Sizes: []int{250}}, config.Change{})
+	assert.Error(t, err)
+
+	cfg, err := store.GetPackConfig(ctx, "")
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.Sizes)
+}
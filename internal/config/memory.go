@@ -0,0 +1,157 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// scope is the current configuration of the global scope or of one product, with every
+// version it had. It is also the layout of a scope in a configuration file.
+type scope struct {
+	ConfigVersion                 // current configuration; version 0 when written by hand
+	History       []ConfigVersion `json:"history,omitempty"` // oldest first
+}
+
+func (s *scope) clone() *scope {
+	out := &scope{ConfigVersion: s.ConfigVersion.clone()}
+	for _, v := range s.History {
+		out.History = append(out.History, v.clone())
+	}
+	return out
+}
+
+// MemoryStore keeps the configuration in process. It suits tests and single-instance
+// deployments that do not need the configuration to survive a restart.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	global   *scope
+	products map[string]*scope
+}
+
+// NewMemoryStore returns an empty store: no global sizes and no products.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{global: &scope{}, products: map[string]*scope{}}
+}
+
+// GetPackConfig returns a copy of the current configuration of the SKU.
+func (s *MemoryStore) GetPackConfig(_ context.Context, sku string) (*ConfigVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sc, err := s.scope(sku)
+	if err != nil {
+		return nil, err
+	}
+	current := sc.ConfigVersion.clone()
+	return &current, nil
+}
+
+// SetPackConfig stores a copy of cfg as the next version of the SKU.
+func (s *MemoryStore) SetPackConfig(_ context.Context, sku string, cfg PackConfig, change Change) (*ConfigVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sc, err := s.scope(sku)
+	if err != nil {
+		sc = &scope{}
+	}
+	if err := change.check(sc.Version); err != nil {
+		return nil, err
+	}
+	if sku != "" {
+		s.products[sku] = sc
+	}
+	next := nextVersion(sc.Version, cfg, change)
+	sc.ConfigVersion = next
+	sc.History = append(sc.History, next.clone())
+
+	saved := next.clone()
+	return &saved, nil
+}
+
+// ListVersions returns copies of every version of the SKU, newest first.
+func (s *MemoryStore) ListVersions(_ context.Context, sku string) ([]ConfigVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sc, err := s.scope(sku)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]ConfigVersion, 0, len(sc.History))
+	for _, v := range slices.Backward(sc.History) {
+		versions = append(versions, v.clone())
+	}
+	return versions, nil
+}
+
+// GetVersion returns a copy of version n of the SKU.
+func (s *MemoryStore) GetVersion(_ context.Context, sku string, n int) (*ConfigVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sc, err := s.scope(sku)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range sc.History {
+		if v.Version == n {
+			found := v.clone()
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, n)
+}
+
+// ListProducts returns the SKUs of all configured products, sorted.
+func (s *MemoryStore) ListProducts(context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	skus := make([]string, 0, len(s.products))
+	for sku := range s.products {
+		skus = append(skus, sku)
+	}
+	sort.Strings(skus)
+	return skus, nil
+}
+
+// scope returns the stored scope of the SKU; the caller holds the lock.
+func (s *MemoryStore) scope(sku string) (*scope, error) {
+	if sku == "" {
+		return s.global, nil
+	}
+	sc, ok := s.products[sku]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProduct, sku)
+	}
+	return sc, nil
+}
+
+// document returns a copy of everything stored, in the layout of a configuration file.
+func (s *MemoryStore) document() fileDocument {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	doc := fileDocument{scope: *s.global.clone(), Products: map[string]*scope{}}
+	for sku, sc := range s.products {
+		doc.Products[sku] = sc.clone()
+	}
+	return doc
+}
+
+// load replaces everything stored with a copy of the document.
+func (s *MemoryStore) load(doc fileDocument) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.global = doc.scope.clone()
+	s.products = make(map[string]*scope, len(doc.Products))
+	for sku, sc := range doc.Products {
+		if sc != nil {
+			s.products[sku] = sc.clone()
+		}
+	}
+}
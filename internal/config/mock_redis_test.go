@@ -1,49 +1,50 @@
 package config_test
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/rapido-liebre/pack_solver/internal/config"
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
 	"github.com/stretchr/testify/assert"
-	"os"
 )
 
-func TestGetPackSizesWithMockRedis(t *testing.T) {
-	// Run miniredis on random port or run it on specific port by .RunAddr("localhost:6380")
+// newMockRedisStore starts miniredis on a random port and returns a store on it.
+func newMockRedisStore(t *testing.T) (*miniredis.Miniredis, *config.RedisStore) {
 	s, err := miniredis.Run()
 	assert.NoError(t, err)
-	defer s.Close()
+	t.Cleanup(s.Close)
 
-	err = os.Setenv("REDIS_ADDR", s.Addr())
+	store, err := config.NewRedisStore(s.Addr())
 	assert.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	return s, store
+}
+
+func TestGetPackSizesWithMockRedis(t *testing.T) {
+	s, store := newMockRedisStore(t)
 
-	err = config.InitRedis()
+	// not configured yet
+	cfg, err := store.GetPackConfig(context.Background(), "")
 	assert.NoError(t, err)
+	assert.Empty(t, cfg.Sizes)
 
 	value, _ := json.Marshal([]int{100, 250, 500})
 	err = s.Set(config.PackSizesKey, string(value))
 	assert.NoError(t, err)
 
-	sizes, err := config.GetPackSizes()
+	cfg, err = store.GetPackConfig(context.Background(), "")
 	assert.NoError(t, err)
-	assert.Equal(t, []int{100, 250, 500}, sizes)
+	assert.Equal(t, []int{100, 250, 500}, cfg.Sizes)
 }
 
 func TestSetPackSizesWithMockRedis(t *testing.T) {
-	s, err := miniredis.Run()
-	assert.NoError(t, err)
-	defer s.Close()
-
-	err = os.Setenv("REDIS_ADDR", s.Addr())
-	assert.NoError(t, err)
-
-	err = config.InitRedis()
-	assert.NoError(t, err)
+	s, store := newMockRedisStore(t)
 
 	sizes := []int{300, 600, 900}
-	err = config.SetPackSizes(sizes)
+	_, err := store.SetPackConfig(context.Background(), "", config.PackConfig{Sizes: sizes}, config.Change{})
 	assert.NoError(t, err)
 
 	stored, _ := s.Get(config.PackSizesKey)
@@ -52,3 +53,63 @@ func TestSetPackSizesWithMockRedis(t *testing.T) {
 	assert.NoError(t, err)
 	assert.ElementsMatch(t, sizes, result)
 }
+
+func TestPackCostsWithMockRedis(t *testing.T) {
+	s, store := newMockRedisStore(t)
+	ctx := context.Background()
+
+	_, err := store.SetPackConfig(ctx, "", config.PackConfig{Sizes: []int{250, 500}, Costs: map[int]float64{250: 0.5, 500: 0.8}}, config.Change{})
+	assert.NoError(t, err)
+	stored, _ := s.Get(config.PackCostsKey)
+	assert.JSONEq(t, `{"250": 0.5, "500": 0.8}`, stored)
+
+	// removing the costs deletes their key
+	_, err = store.SetPackConfig(ctx, "", config.PackConfig{Sizes: []int{250, 500}}, config.Change{})
+	assert.NoError(t, err)
+	assert.False(t, s.Exists(config.PackCostsKey))
+
+	cfg, err := store.GetPackConfig(ctx, "")
+	assert.NoError(t, err)
+	assert.NotNil(t, cfg.Costs)
+	assert.Empty(t, cfg.Costs)
+}
+
+func TestProductPackConfigWithMockRedis(t *testing.T) {
+	s, store := newMockRedisStore(t)
+	ctx := context.Background()
+
+	_, err := store.GetPackConfig(ctx, "SKU-1")
+	assert.ErrorIs(t, err, config.ErrUnknownProduct)
+
+	set(t, store, "SKU-2", config.PackConfig{Sizes: []int{6, 12}})
+	set(t, store, "SKU-1", config.PackConfig{Sizes: []int{10, 20}, Costs: map[int]float64{10: 1, 20: 1.5}})
+
+	// every part keeps its own key
+	stored, _ := s.Get("product:SKU-1:sizes")
+	assert.JSONEq(t, `[10, 20]`, stored)
+	assert.True(t, s.Exists("product:SKU-1:costs"))
+	assert.False(t, s.Exists("product:SKU-2:costs"))
+	members, _ := s.SMembers(config.ProductsKey)
+	assert.ElementsMatch(t, []string{"SKU-1", "SKU-2"}, members)
+
+	// the global configuration is untouched
+	assert.False(t, s.Exists(config.PackSizesKey))
+}
+
+func TestPackagingWithMockRedis(t *testing.T) {
+	s, store := newMockRedisStore(t)
+	ctx := context.Background()
+
+	levels := []packsolver.PackagingLevel{
+		{Name: "case", PerPack: map[int]int{500: 12, 1000: 6}},
+		{Name: "pallet", Capacity: 40},
+	}
+	set(t, store, "", config.PackConfig{Sizes: []int{500, 1000}, Packaging: levels})
+	assert.True(t, s.Exists(config.PackagingKey))
+
+	// configurations written before the store existed are read from the same keys
+	s.Set(config.PackagingKey, `[{"name": "case", "per_pack": {"500": 10, "1000": 5}}]`)
+	cfg, err := store.GetPackConfig(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []packsolver.PackagingLevel{{Name: "case", PerPack: map[int]int{500: 10, 1000: 5}}}, cfg.Packaging)
+}
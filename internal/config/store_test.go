@@ -0,0 +1,83 @@
+package config_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/config"
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"github.com/stretchr/testify/assert"
+)
+
+// stores returns one store of every backend, each starting empty.
+func stores(t *testing.T) map[string]config.ConfigStore {
+	_, redisStore := newMockRedisStore(t)
+	fileStore, err := config.NewFileStore(filepath.Join(t.TempDir(), "packs.json"))
+	assert.NoError(t, err)
+	return map[string]config.ConfigStore{
+		"redis":  redisStore,
+		"memory": config.NewMemoryStore(),
+		"file":   fileStore,
+	}
+}
+
+// set stores cfg as the next version of the SKU.
+func set(t *testing.T, store config.ConfigStore, sku string, cfg config.PackConfig) *config.ConfigVersion {
+	saved, err := store.SetPackConfig(context.Background(), sku, cfg, config.Change{})
+	assert.NoError(t, err)
+	return saved
+}
+
+func TestConfigStores(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := store.GetPackConfig(ctx, "")
+			assert.NoError(t, err)
+			assert.Empty(t, cfg.Sizes)
+			assert.NotNil(t, cfg.Costs)
+
+			_, err = store.GetPackConfig(ctx, "SKU-1")
+			assert.ErrorIs(t, err, config.ErrUnknownProduct)
+
+			skus, err := store.ListProducts(ctx)
+			assert.NoError(t, err)
+			assert.Empty(t, skus)
+
+			global := config.PackConfig{
+				Sizes:     []int{250, 500, 1000},
+				Costs:     map[int]float64{250: 0.5, 500: 0.8, 1000: 1.2},
+				Packaging: []packsolver.PackagingLevel{{Name: "case", PerPack: map[int]int{250: 20, 500: 10, 1000: 5}}},
+			}
+			set(t, store, "", global)
+			set(t, store, "SKU-2", config.PackConfig{Sizes: []int{6, 12}})
+			set(t, store, "SKU-1", config.PackConfig{Sizes: []int{10, 20}})
+
+			cfg, err = store.GetPackConfig(ctx, "")
+			assert.NoError(t, err)
+			assert.Equal(t, global, cfg.PackConfig)
+
+			cfg, err = store.GetPackConfig(ctx, "SKU-1")
+			assert.NoError(t, err)
+			assert.Equal(t, config.PackConfig{Sizes: []int{10, 20}, Costs: map[int]float64{}}, cfg.PackConfig)
+
+			skus, err = store.ListProducts(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"SKU-1", "SKU-2"}, skus)
+
+			// the returned configuration is a copy
+			cfg.Sizes[0] = 99
+			cfg, err = store.GetPackConfig(ctx, "SKU-1")
+			assert.NoError(t, err)
+			assert.Equal(t, []int{10, 20}, cfg.Sizes)
+
+			// a configuration without costs or packaging removes them
+			set(t, store, "", config.PackConfig{Sizes: []int{250}})
+			cfg, err = store.GetPackConfig(ctx, "")
+			assert.NoError(t, err)
+			assert.Empty(t, cfg.Costs)
+			assert.Empty(t, cfg.Packaging)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnknownVersion is returned for a configuration version that was never stored.
+var ErrUnknownVersion = errors.New("unknown config version")
+
+// VersionInfo describes one change of a pack configuration.
+type VersionInfo struct {
+	Version    int       `json:"version"` // 1 for the first change, then +1 for every change; 0 when never changed
+	CreatedAt  time.Time `json:"created_at,omitzero"`
+	Author     string    `json:"author,omitempty"`
+	Comment    string    `json:"comment,omitempty"`
+	RollbackOf int       `json:"rollback_of,omitempty"` // version this one restored, for rollbacks
+}
+
+// ConfigVersion is a pack configuration as stored by one change.
+type ConfigVersion struct {
+	VersionInfo
+	PackConfig
+}
+
+// VersionConflictError is returned when a change expected another current version, e.g.
+// because somebody else changed the configuration since it was read.
+type VersionConflictError struct {
+	Expected int
+	Current  int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("config version %d expected, current version is %d", e.Expected, e.Current)
+}
+
+// Change carries who made a change and why; all fields are optional.
+type Change struct {
+	Author     string
+	Comment    string
+	RollbackOf int
+	// IfVersion, when set, stores the change only while it is the current version;
+	// otherwise the store returns a *VersionConflictError and keeps the configuration.
+	IfVersion *int
+}
+
+// check returns a *VersionConflictError when the change requires another current version.
+func (c Change) check(current int) error {
+	if c.IfVersion != nil && *c.IfVersion != current {
+		return &VersionConflictError{Expected: *c.IfVersion, Current: current}
+	}
+	return nil
+}
+
+// Rollback makes version n of the SKU the current configuration again. The history is
+// never rewritten: the restored configuration is stored as a new version. The IfVersion
+// precondition of the change applies as for any other change.
+func Rollback(ctx context.Context, store ConfigStore, sku string, n int, change Change) (*ConfigVersion, error) {
+	old, err := store.GetVersion(ctx, sku, n)
+	if err != nil {
+		return nil, err
+	}
+	change.RollbackOf = n
+	if change.Comment == "" {
+		change.Comment = fmt.Sprintf("rollback to version %d", n)
+	}
+	return store.SetPackConfig(ctx, sku, old.PackConfig, change)
+}
+
+// nextVersion returns the version a change to cfg creates after the current version.
+func nextVersion(current int, cfg PackConfig, change Change) ConfigVersion {
+	return ConfigVersion{
+		VersionInfo: VersionInfo{
+			Version:    current + 1,
+			CreatedAt:  time.Now().UTC(),
+			Author:     change.Author,
+			Comment:    change.Comment,
+			RollbackOf: change.RollbackOf,
+		},
+		PackConfig: cfg.clone(),
+	}
+}
+
+// clone returns a deep copy of the version.
+func (v ConfigVersion) clone() ConfigVersion {
+	return ConfigVersion{VersionInfo: v.VersionInfo, PackConfig: v.PackConfig.clone()}
+}
@@ -0,0 +1,165 @@
+package config_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigVersions(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			versions, err := store.ListVersions(ctx, "")
+			assert.NoError(t, err)
+			assert.Empty(t, versions)
+			_, err = store.ListVersions(ctx, "SKU-1")
+			assert.ErrorIs(t, err, config.ErrUnknownProduct)
+
+			first, err := store.SetPackConfig(ctx, "", config.PackConfig{Sizes: []int{250, 500}}, config.Change{Author: "alice", Comment: "initial sizes"})
+			assert.NoError(t, err)
+			assert.Equal(t, 1, first.Version)
+			assert.False(t, first.CreatedAt.IsZero())
+			second := set(t, store, "", config.PackConfig{Sizes: []int{250, 500, 1000}})
+			assert.Equal(t, 2, second.Version)
+
+			current, err := store.GetPackConfig(ctx, "")
+			assert.NoError(t, err)
+			assert.Equal(t, second.VersionInfo, current.VersionInfo)
+
+			versions, err = store.ListVersions(ctx, "")
+			assert.NoError(t, err)
+			if assert.Len(t, versions, 2) {
+				assert.Equal(t, 2, versions[0].Version)
+				assert.Equal(t, 1, versions[1].Version)
+				assert.Equal(t, "alice", versions[1].Author)
+				assert.Equal(t, "initial sizes", versions[1].Comment)
+				assert.Equal(t, []int{250, 500}, versions[1].Sizes)
+			}
+
+			v, err := store.GetVersion(ctx, "", 1)
+			assert.NoError(t, err)
+			assert.Equal(t, []int{250, 500}, v.Sizes)
+			_, err = store.GetVersion(ctx, "", 3)
+			assert.ErrorIs(t, err, config.ErrUnknownVersion)
+			_, err = store.GetVersion(ctx, "SKU-1", 1)
+			assert.ErrorIs(t, err, config.ErrUnknownProduct)
+
+			// products count their own versions
+			product := set(t, store, "SKU-1", config.PackConfig{Sizes: []int{6, 12}})
+			assert.Equal(t, 1, product.Version)
+			_, err = store.GetVersion(ctx, "SKU-1", 2)
+			assert.ErrorIs(t, err, config.ErrUnknownVersion)
+		})
+	}
+}
+
+func TestRollback(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			set(t, store, "", config.PackConfig{Sizes: []int{250, 500}, Costs: map[int]float64{250: 1, 500: 1.5}})
+			set(t, store, "", config.PackConfig{Sizes: []int{300}})
+
+			restored, err := config.Rollback(ctx, store, "", 1, config.Change{Author: "bob"})
+			assert.NoError(t, err)
+			assert.Equal(t, 3, restored.Version)
+			assert.Equal(t, 1, restored.RollbackOf)
+			assert.Equal(t, "bob", restored.Author)
+			assert.Equal(t, "rollback to version 1", restored.Comment)
+
+			current, err := store.GetPackConfig(ctx, "")
+			assert.NoError(t, err)
+			assert.Equal(t, []int{250, 500}, current.Sizes)
+			assert.Equal(t, map[int]float64{250: 1, 500: 1.5}, current.Costs)
+
+			// the history is kept
+			versions, err := store.ListVersions(ctx, "")
+			assert.NoError(t, err)
+			assert.Len(t, versions, 3)
+
+			_, err = config.Rollback(ctx, store, "", 7, config.Change{})
+			assert.ErrorIs(t, err, config.ErrUnknownVersion)
+		})
+	}
+}
+
+func TestIfVersion(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			zero, one := 0, 1
+
+			first, err := store.SetPackConfig(ctx, "", config.PackConfig{Sizes: []int{250}}, config.Change{IfVersion: &zero})
+			assert.NoError(t, err)
+			assert.Equal(t, 1, first.Version)
+
+			// a second writer that also read version 0 loses
+			_, err = store.SetPackConfig(ctx, "", config.PackConfig{Sizes: []int{500}}, config.Change{IfVersion: &zero})
+			var conflict *config.VersionConflictError
+			if assert.ErrorAs(t, err, &conflict) {
+				assert.Equal(t, 0, conflict.Expected)
+				assert.Equal(t, 1, conflict.Current)
+			}
+			current, err := store.GetPackConfig(ctx, "")
+			assert.NoError(t, err)
+			assert.Equal(t, 1, current.Version)
+			assert.Equal(t, []int{250}, current.Sizes)
+
+			second, err := store.SetPackConfig(ctx, "", config.PackConfig{Sizes: []int{500}}, config.Change{IfVersion: &one})
+			assert.NoError(t, err)
+			assert.Equal(t, 2, second.Version)
+
+			_, err = config.Rollback(ctx, store, "", 1, config.Change{IfVersion: &one})
+			assert.ErrorAs(t, err, &conflict)
+
+			// a failed precondition does not register the product
+			_, err = store.SetPackConfig(ctx, "SKU-1", config.PackConfig{Sizes: []int{6}}, config.Change{IfVersion: &one})
+			assert.ErrorAs(t, err, &conflict)
+			products, err := store.ListProducts(ctx)
+			assert.NoError(t, err)
+			assert.Empty(t, products)
+		})
+	}
+}
+
+func TestFileStoreKeepsHistory(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "packs.yaml")
+	store, err := config.NewFileStore(path)
+	assert.NoError(t, err)
+	set(t, store, "", config.PackConfig{Sizes: []int{250}})
+	set(t, store, "", config.PackConfig{Sizes: []int{250, 500}})
+
+	reopened, err := config.NewFileStore(path)
+	assert.NoError(t, err)
+	v, err := reopened.GetVersion(ctx, "", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{250}, v.Sizes)
+	next := set(t, reopened, "", config.PackConfig{Sizes: []int{500}})
+	assert.Equal(t, 3, next.Version)
+}
+
+func TestRedisStoreConcurrentChanges(t *testing.T) {
+	_, store := newMockRedisStore(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			set(t, store, "", config.PackConfig{Sizes: []int{250}})
+		}()
+	}
+	wg.Wait()
+
+	// every change got its own version
+	versions, err := store.ListVersions(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Len(t, versions, 8)
+	assert.Equal(t, 8, versions[0].Version)
+}
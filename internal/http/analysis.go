@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+)
+
+type AnalysisResponse struct {
+	*packsolver.Analysis
+	Range *packsolver.RangeAnalysis `json:"range,omitempty"` // set when from and to are given
+}
+
+// @Summary Analyze the pack configuration
+// @Description Reports the GCD of the stored pack sizes, the largest quantity that cannot be filled exactly
+// @Description (Frobenius number, null when the GCD is above 1) and the worst-case overage over all quantities.
+// @Description With from and to the overage is also summarized for that quantity range (at most 16777216 quantities).
+// @Tags config
+// @Produce json
+// @Param sku query string false "Analyze the configuration of this product instead of the global one"
+// @Param from query int false "First quantity of the range"
+// @Param to query int false "Last quantity of the range"
+// @Success 200 {object} AnalysisResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /config/packs/analysis [get]
+func (a *API) analyzePackSizes(c *gin.Context) {
+	sku := c.Query("sku")
+	if sku != "" && !skuPattern.MatchString(sku) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sku"})
+		return
+	}
+
+	var from, to int
+	withRange := c.Query("from") != "" || c.Query("to") != ""
+	if withRange {
+		var errFrom, errTo error
+		from, errFrom = strconv.Atoi(c.Query("from"))
+		to, errTo = strconv.Atoi(c.Query("to"))
+		if errFrom != nil || errTo != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must both be integers"})
+			return
+		}
+	}
+
+	cfg, failure := a.loadPackConfig(c.Request.Context(), sku)
+	if failure != nil {
+		c.JSON(failure.Status, failure.Body)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), solveTimeout())
+	defer cancel()
+
+	var resp AnalysisResponse
+	var err error
+	if resp.Analysis, err = packsolver.AnalyzeContext(ctx, cfg.Sizes); err == nil && withRange {
+		resp.Range, err = packsolver.AnalyzeRangeContext(ctx, cfg.Sizes, from, to)
+	}
+	if errors.Is(err, packsolver.ErrInvalidRange) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "invalid_range"})
+		return
+	}
+	if err != nil {
+		failure := solveFailure(err)
+		c.JSON(failure.Status, failure.Body)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
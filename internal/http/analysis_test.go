@@ -0,0 +1,57 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpapi "github.com/rapido-liebre/pack_solver/internal/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalysisEndpoint(t *testing.T) {
+	store := setupStore(t, []int{6, 9, 20})
+	r := httpapi.SetupRouter(store)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/config/packs/analysis?from=1&to=100", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	var resp httpapi.AnalysisResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.GCD)
+	if assert.NotNil(t, resp.Frobenius) {
+		assert.Equal(t, 43, *resp.Frobenius)
+	}
+	if assert.NotNil(t, resp.Range) {
+		assert.Equal(t, 100, resp.Range.To)
+		assert.LessOrEqual(t, resp.Range.WorstOverage, resp.WorstOverage)
+	}
+}
+
+func TestAnalysisEndpointGCD(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/config/packs/analysis", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"frobenius":null`)
+	assert.Contains(t, w.Body.String(), `"gcd":250`)
+	assert.NotContains(t, w.Body.String(), `"range"`)
+}
+
+func TestAnalysisEndpointInvalidRange(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	for _, query := range []string{"?from=10", "?from=a&to=5", "?from=10&to=5", "?from=0&to=5"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/config/packs/analysis"+query, nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, 400, w.Code, query)
+	}
+}
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rapido-liebre/pack_solver/internal/config"
+)
+
+// maxBatchLines caps the number of order lines accepted by POST /orders/batch.
+const maxBatchLines = 1000
+
+// BatchOrderRequest holds the order lines of a batch. Plain quantities and full line
+// items can be mixed; quantities are appended after the lines and solved with the defaults.
+// Lines naming a sku are solved with the pack configuration of that product.
+type BatchOrderRequest struct {
+	Lines      []OrderRequest `json:"lines,omitempty"`
+	Quantities []int          `json:"quantities,omitempty"`
+}
+
+// BatchOrderResult is the outcome of one order line, in the same position as the input.
+type BatchOrderResult struct {
+	Status int            `json:"status"`          // HTTP status the line would get from POST /order
+	Order  *OrderResponse `json:"order,omitempty"` // set when Status is 200
+	Error  map[string]any `json:"error,omitempty"` // same body as an error from POST /order
+}
+
+type BatchOrderResponse struct {
+	Results []BatchOrderResult `json:"results"`
+}
+
+// batchWorkers returns the number of lines solved in parallel, from BATCH_WORKERS or the
+// number of CPUs when it is unset or invalid.
+func batchWorkers() int {
+	if v := os.Getenv("BATCH_WORKERS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return runtime.NumCPU()
+}
+
+// @Summary Calculate pack distributions for many order lines
+// @Description Solves up to 1000 order lines in one call. The pack configuration is loaded once and the
+// @Description lines are solved in parallel by a bounded worker pool (BATCH_WORKERS, defaults to the CPU count).
+// @Description Results keep the input order; every line reports its own status and error.
+// @Description Lines may name a sku to use the pack sizes of that product, e.g. {"lines": [{"sku": "A", "quantity": 10}]}.
+// @Tags order
+// @Accept json
+// @Produce json
+// @Param request body BatchOrderRequest true "Order lines"
+// @Success 200 {object} BatchOrderResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /orders/batch [post]
+func (a *API) createOrderBatch(c *gin.Context) {
+	var req BatchOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid batch payload"})
+		return
+	}
+
+	lines := req.Lines
+	for _, q := range req.Quantities {
+		lines = append(lines, OrderRequest{Quantity: q})
+	}
+	if len(lines) == 0 || len(lines) > maxBatchLines {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "batch must contain between 1 and 1000 lines"})
+		return
+	}
+
+	// Every configuration is loaded once per batch. A product that is not configured only
+	// fails its own lines, but a configuration that cannot be read fails the whole batch.
+	configs := map[string]batchConfig{}
+	for _, line := range lines {
+		if _, ok := configs[line.SKU]; ok || (line.SKU != "" && !skuPattern.MatchString(line.SKU)) {
+			continue
+		}
+		cfg, failure := a.loadPackConfig(c.Request.Context(), line.SKU)
+		if failure != nil && failure.Status == http.StatusInternalServerError {
+			c.JSON(failure.Status, failure.Body)
+			return
+		}
+		configs[line.SKU] = batchConfig{ConfigVersion: cfg, failure: failure}
+	}
+
+	results := make([]BatchOrderResult, len(lines))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < min(batchWorkers(), len(lines)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = a.solveBatchLine(c.Request.Context(), lines[i], configs[lines[i].SKU])
+			}
+		}()
+	}
+	for i := range lines {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	c.JSON(http.StatusOK, BatchOrderResponse{Results: results})
+}
+
+// batchConfig is the pack configuration of one SKU, or why it could not be loaded.
+type batchConfig struct {
+	*config.ConfigVersion
+	failure *orderFailure
+}
+
+// solveBatchLine solves one line with its own solver deadline, so a slow line only
+// times out itself.
+func (a *API) solveBatchLine(parent context.Context, line OrderRequest, cfg batchConfig) BatchOrderResult {
+	if failure := line.normalize(); failure != nil {
+		return BatchOrderResult{Status: failure.Status, Error: failure.Body}
+	}
+	if cfg.failure != nil {
+		return BatchOrderResult{Status: cfg.failure.Status, Error: cfg.failure.Body}
+	}
+
+	ctx, cancel := context.WithTimeout(parent, solveTimeout())
+	defer cancel()
+
+	resp, failure := a.solveOrder(ctx, line, cfg.ConfigVersion)
+	if failure != nil {
+		return BatchOrderResult{Status: failure.Status, Error: failure.Body}
+	}
+	return BatchOrderResult{Status: http.StatusOK, Order: resp}
+}
@@ -0,0 +1,139 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/config"
+	httpapi "github.com/rapido-liebre/pack_solver/internal/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderBatchKeepsInputOrder(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	body := `{
+		"lines": [
+			{"quantity": 1},
+			{"quantity": 1700, "exact": true},
+			{"quantity": 251, "strategy": "quantum"},
+			{"quantity": 12001, "strategy": "greedy"}
+		],
+		"quantities": [501, 1000]
+	}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/orders/batch", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	var resp httpapi.BatchOrderResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	if !assert.Len(t, resp.Results, 6) {
+		return
+	}
+
+	assert.Equal(t, 200, resp.Results[0].Status)
+	assert.Equal(t, 250, resp.Results[0].Order.TotalItems)
+
+	assert.Equal(t, 422, resp.Results[1].Status)
+	assert.Equal(t, "overage_exceeded", resp.Results[1].Error["code"])
+
+	assert.Equal(t, 400, resp.Results[2].Status)
+	assert.Nil(t, resp.Results[2].Order)
+
+	assert.Equal(t, 200, resp.Results[3].Status)
+	assert.Equal(t, "greedy", resp.Results[3].Order.Strategy)
+
+	assert.Equal(t, 750, resp.Results[4].Order.TotalItems)
+	assert.Equal(t, 1000, resp.Results[5].Order.TotalItems)
+}
+
+func TestOrderBatchLoadsConfigOnce(t *testing.T) {
+	s, store := setupMockRedis(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	quantities := make([]string, 200)
+	for i := range quantities {
+		quantities[i] = fmt.Sprint(i*37 + 1)
+	}
+	body := `{"quantities": [` + strings.Join(quantities, ",") + `]}`
+
+	before := s.CommandCount()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/orders/batch", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	// a single read of the configuration, regardless of the number of lines
+	assert.Equal(t, 1, s.CommandCount()-before)
+
+	var resp httpapi.BatchOrderResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Results, 200)
+	for i, res := range resp.Results {
+		assert.Equal(t, 200, res.Status)
+		assert.GreaterOrEqual(t, res.Order.TotalItems, i*37+1)
+	}
+}
+
+func TestOrderBatchInvalidPayload(t *testing.T) {
+	r := httpapi.SetupRouter(config.NewMemoryStore())
+
+	for _, body := range []string{`{}`, `{"lines": []}`, `not json`} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/orders/batch", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, 400, w.Code, body)
+	}
+}
+
+func TestOrderBatchWithSKUs(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	for sku, sizes := range map[string]string{"WIDGET": `[6, 12, 24]`, "GIZMO": `[5, 10]`} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/config/products/"+sku+"/packs", bytes.NewBufferString(`{"pack_sizes": `+sizes+`}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, 200, w.Code)
+	}
+
+	body := `{
+		"lines": [
+			{"sku": "WIDGET", "quantity": 30},
+			{"sku": "GIZMO", "quantity": 31},
+			{"sku": "GADGET", "quantity": 1},
+			{"quantity": 1},
+			{"sku": "WIDGET", "quantity": 7}
+		]
+	}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/orders/batch", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	var resp httpapi.BatchOrderResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	if !assert.Len(t, resp.Results, 5) {
+		return
+	}
+
+	assert.Equal(t, "WIDGET", resp.Results[0].Order.SKU)
+	assert.Equal(t, 30, resp.Results[0].Order.TotalItems)
+	assert.Equal(t, 35, resp.Results[1].Order.TotalItems)
+	assert.Equal(t, 404, resp.Results[2].Status)
+	assert.Equal(t, "unknown_product", resp.Results[2].Error["code"])
+	assert.Equal(t, 250, resp.Results[3].Order.TotalItems)
+	assert.Equal(t, 12, resp.Results[4].Order.TotalItems)
+}
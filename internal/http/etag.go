@@ -0,0 +1,45 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rapido-liebre/pack_solver/internal/config"
+)
+
+// versionETag returns the strong entity tag of a configuration version, e.g. "3".
+func versionETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// ifMatchVersion returns the configuration version the If-Match header requires, or nil when
+// the header is absent or "*". It answers 400 when the header is not a single version ETag.
+// Weak tags never match, as If-Match compares strongly.
+func ifMatchVersion(c *gin.Context) (*int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil, true
+	}
+	if strings.HasPrefix(header, "W/") {
+		// a version can never equal -1, so the change fails with 412
+		weak := -1
+		return &weak, true
+	}
+	version, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(header, `"`), `"`))
+	if err != nil || version < 0 || len(header) < 3 || header[0] != '"' || header[len(header)-1] != '"' {
+		c.JSON(http.StatusBadRequest, gin.H{"error": `If-Match must be a single ETag such as "3", as returned by GET`, "code": "invalid_if_match"})
+		return nil, false
+	}
+	return &version, true
+}
+
+// conflictFailure answers a change whose If-Match version is no longer current.
+func conflictFailure(err *config.VersionConflictError) *orderFailure {
+	return &orderFailure{Status: http.StatusPreconditionFailed, Body: gin.H{
+		"error":           "pack configuration was changed in the meantime, reload it and try again",
+		"code":            "version_mismatch",
+		"current_version": err.Current,
+	}}
+}
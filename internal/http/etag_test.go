@@ -0,0 +1,132 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rapido-liebre/pack_solver/internal/config"
+	httpapi "github.com/rapido-liebre/pack_solver/internal/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// serveIfMatch sends a JSON request with an If-Match header to the router.
+func serveIfMatch(r *gin.Engine, method, path, ifMatch, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", ifMatch)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestPackConfigETag(t *testing.T) {
+	r := httpapi.SetupRouter(config.NewMemoryStore())
+
+	w := serve(r, "GET", "/config/packs", "")
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `"0"`, w.Header().Get("ETag"))
+
+	// two admins edit version 0; the second one must not overwrite the first
+	w = serveIfMatch(r, "POST", "/config/packs", `"0"`, `{"pack_sizes": [250, 500]}`)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+
+	w = serveIfMatch(r, "POST", "/config/packs", `"0"`, `{"pack_sizes": [300]}`)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "version_mismatch", body["code"])
+	assert.Equal(t, float64(1), body["current_version"])
+
+	w = serve(r, "GET", "/config/packs", "")
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	assert.Contains(t, w.Body.String(), `"pack_sizes":[250,500]`)
+
+	// after reloading, the change goes through
+	w = serveIfMatch(r, "POST", "/config/packs", `"1"`, `{"pack_sizes": [300]}`)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+
+	// "*" and no header skip the check
+	w = serveIfMatch(r, "POST", "/config/packs", "*", `{"pack_sizes": [400]}`)
+	assert.Equal(t, 200, w.Code)
+	w = serve(r, "POST", "/config/packs", `{"pack_sizes": [500]}`)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+
+	// weak tags never match
+	w = serveIfMatch(r, "POST", "/config/packs", `W/"4"`, `{"pack_sizes": [600]}`)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	for _, header := range []string{`4`, `"four"`, `"4", "5"`, `"-1"`} {
+		w = serveIfMatch(r, "POST", "/config/packs", header, `{"pack_sizes": [600]}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code, header)
+		assert.Contains(t, w.Body.String(), "invalid_if_match", header)
+	}
+}
+
+func TestRollbackIfMatch(t *testing.T) {
+	r := httpapi.SetupRouter(config.NewMemoryStore())
+	serve(r, "POST", "/config/packs", `{"pack_sizes": [250]}`)
+	serve(r, "POST", "/config/packs", `{"pack_sizes": [500]}`)
+
+	w := serveIfMatch(r, "POST", "/config/packs/rollback/1", `"1"`, "")
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Contains(t, w.Body.String(), "version_mismatch")
+
+	w = serveIfMatch(r, "POST", "/config/packs/rollback/1", `"2"`, "")
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+}
+
+func TestProductPacksIfMatch(t *testing.T) {
+	r := httpapi.SetupRouter(config.NewMemoryStore())
+
+	// "0" creates a product only once
+	w := serveIfMatch(r, "POST", "/config/products/SKU-1/packs", `"0"`, `{"pack_sizes": [6, 12]}`)
+	assert.Equal(t, 200, w.Code)
+	w = serveIfMatch(r, "POST", "/config/products/SKU-1/packs", `"0"`, `{"pack_sizes": [24]}`)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	w = serve(r, "GET", "/config/products/SKU-1/packs", "")
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+}
+
+func TestPackConfigIfMatchRedis(t *testing.T) {
+	_, store := setupMockRedis(t, []int{250, 500})
+	r := httpapi.SetupRouter(store)
+
+	w := serve(r, "GET", "/config/packs", "")
+	etag := w.Header().Get("ETag")
+	w = serveIfMatch(r, "POST", "/config/packs", etag, `{"pack_sizes": [300]}`)
+	assert.Equal(t, 200, w.Code)
+	w = serveIfMatch(r, "POST", "/config/packs", etag, `{"pack_sizes": [400]}`)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	cfg, err := store.GetPackConfig(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{300}, cfg.Sizes)
+}
+
+func TestPackConfigIfMatchBeforeKeptCosts(t *testing.T) {
+	r := httpapi.SetupRouter(config.NewMemoryStore())
+	serve(r, "POST", "/config/packs", `{"pack_sizes": [250, 500], "pack_costs": {"250": 1, "500": 1.5}}`)
+	serve(r, "POST", "/config/packs", `{"pack_sizes": [250, 500, 1000], "pack_costs": {"250": 1, "500": 1.5, "1000": 2.5}}`)
+
+	// the kept costs would come from version 2, not from the edited version 1
+	w := serveIfMatch(r, "POST", "/config/packs", `"1"`, `{"pack_sizes": [250, 500]}`)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Contains(t, w.Body.String(), "version_mismatch")
+
+	w = serveIfMatch(r, "POST", "/config/packs", `"2"`, `{"pack_sizes": [250, 1000], "pack_costs": {"250": 1, "1000": 2.5}}`)
+	assert.Equal(t, 200, w.Code)
+	w = serveIfMatch(r, "POST", "/config/packs", `"3"`, `{"pack_sizes": [1000, 250]}`)
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"pack_costs":{"1000":2.5,"250":1}`)
+}
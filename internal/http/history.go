@@ -0,0 +1,159 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rapido-liebre/pack_solver/internal/config"
+)
+
+type ConfigHistoryResponse struct {
+	Versions []config.ConfigVersion `json:"versions"` // newest first
+}
+
+// RollbackRequest optionally records who rolled back and why.
+type RollbackRequest struct {
+	Author  string `json:"author,omitempty"`
+	Comment string `json:"comment,omitempty"` // defaults to "rollback to version n"
+}
+
+// @Summary List pack configuration versions
+// @Description Returns every stored version of the pack configuration, newest first. Each change creates a version
+// @Description with a timestamp and the optional author and comment of the request.
+// @Tags config
+// @Produce json
+// @Param sku query string false "List the versions of this product instead of the global configuration"
+// @Success 200 {object} ConfigHistoryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /config/packs/history [get]
+func (a *API) getPackHistory(c *gin.Context) {
+	sku, ok := historySKU(c)
+	if !ok {
+		return
+	}
+
+	versions, err := a.store.ListVersions(c.Request.Context(), sku)
+	if err != nil {
+		failure := historyFailure(err)
+		c.JSON(failure.Status, failure.Body)
+		return
+	}
+	c.JSON(http.StatusOK, ConfigHistoryResponse{Versions: versions})
+}
+
+// @Summary Get a pack configuration version
+// @Description Returns one stored version of the pack configuration
+// @Tags config
+// @Produce json
+// @Param n path int true "Version number"
+// @Param sku query string false "Read a version of this product instead of the global configuration"
+// @Success 200 {object} config.ConfigVersion
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /config/packs/versions/{n} [get]
+func (a *API) getPackVersion(c *gin.Context) {
+	sku, ok := historySKU(c)
+	if !ok {
+		return
+	}
+	n, ok := versionParam(c)
+	if !ok {
+		return
+	}
+
+	v, err := a.store.GetVersion(c.Request.Context(), sku, n)
+	if err != nil {
+		failure := historyFailure(err)
+		c.JSON(failure.Status, failure.Body)
+		return
+	}
+	c.JSON(http.StatusOK, v)
+}
+
+// @Summary Roll back the pack configuration
+// @Description Makes version n the current pack configuration again. The history is kept: the restored
+// @Description configuration is stored as a new version with rollback_of set to n. If-Match works as for POST /config/packs.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Param n path int true "Version to restore"
+// @Param sku query string false "Roll back this product instead of the global configuration"
+// @Param request body RollbackRequest false "Author and comment"
+// @Param If-Match header string false "ETag of the version the rollback is based on"
+// @Success 200 {object} config.ConfigVersion
+// @Header 200 {string} ETag "Version created by the rollback"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]any
+// @Failure 500 {object} map[string]string
+// @Router /config/packs/rollback/{n} [post]
+func (a *API) rollbackPackConfig(c *gin.Context) {
+	sku, ok := historySKU(c)
+	if !ok {
+		return
+	}
+	n, ok := versionParam(c)
+	if !ok {
+		return
+	}
+	ifVersion, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	var req RollbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rollback payload"})
+		return
+	}
+
+	saved, err := config.Rollback(c.Request.Context(), a.store, sku, n, config.Change{Author: req.Author, Comment: req.Comment, IfVersion: ifVersion})
+	if err != nil {
+		failure := historyFailure(err)
+		c.JSON(failure.Status, failure.Body)
+		return
+	}
+	a.tableCache(sku).Invalidate()
+	c.Header("ETag", versionETag(saved.Version))
+	c.JSON(http.StatusOK, saved)
+}
+
+// historySKU returns the sku query parameter, answering 400 when it is invalid.
+func historySKU(c *gin.Context) (string, bool) {
+	sku := c.Query("sku")
+	if sku != "" && !skuPattern.MatchString(sku) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sku"})
+		return "", false
+	}
+	return sku, true
+}
+
+// versionParam returns the version number in the path, answering 400 when it is invalid.
+func versionParam(c *gin.Context) (int, bool) {
+	n, err := strconv.Atoi(c.Param("n"))
+	if err != nil || n < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version must be a positive integer"})
+		return 0, false
+	}
+	return n, true
+}
+
+// historyFailure maps a store error to an HTTP status and a machine-readable error code.
+func historyFailure(err error) *orderFailure {
+	var conflict *config.VersionConflictError
+	switch {
+	case errors.As(err, &conflict):
+		return conflictFailure(conflict)
+	case errors.Is(err, config.ErrUnknownProduct):
+		return &orderFailure{Status: http.StatusNotFound, Body: gin.H{"error": err.Error(), "code": "unknown_product"}}
+	case errors.Is(err, config.ErrUnknownVersion):
+		return &orderFailure{Status: http.StatusNotFound, Body: gin.H{"error": err.Error(), "code": "unknown_version"}}
+	default:
+		return &orderFailure{Status: http.StatusInternalServerError, Body: gin.H{"error": "could not access config history"}}
+	}
+}
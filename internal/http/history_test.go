@@ -0,0 +1,118 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rapido-liebre/pack_solver/internal/config"
+	httpapi "github.com/rapido-liebre/pack_solver/internal/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// serve sends a JSON request to the router and returns the recorded response.
+func serve(r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestConfigHistoryAndRollback(t *testing.T) {
+	r := httpapi.SetupRouter(config.NewMemoryStore())
+
+	w := serve(r, "POST", "/config/packs", `{"pack_sizes": [250, 500, 1000], "author": "alice", "comment": "initial sizes"}`)
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"version":1`)
+	w = serve(r, "POST", "/config/packs", `{"pack_sizes": [300, 700]}`)
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"version":2`)
+
+	var order httpapi.OrderResponse
+	w = serve(r, "POST", "/order", `{"quantity": 251}`)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+	assert.Equal(t, 2, order.ConfigVersion)
+	assert.Equal(t, 300, order.TotalItems)
+
+	var history httpapi.ConfigHistoryResponse
+	w = serve(r, "GET", "/config/packs/history", "")
+	assert.Equal(t, 200, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+	if assert.Len(t, history.Versions, 2) {
+		assert.Equal(t, 2, history.Versions[0].Version)
+		assert.Equal(t, "alice", history.Versions[1].Author)
+		assert.Equal(t, "initial sizes", history.Versions[1].Comment)
+	}
+
+	var version config.ConfigVersion
+	w = serve(r, "GET", "/config/packs/versions/1", "")
+	assert.Equal(t, 200, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &version))
+	assert.Equal(t, []int{250, 500, 1000}, version.Sizes)
+
+	// rolling back creates version 3 with the sizes of version 1, and orders use them at once
+	w = serve(r, "POST", "/config/packs/rollback/1", `{"author": "bob"}`)
+	assert.Equal(t, 200, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &version))
+	assert.Equal(t, 3, version.Version)
+	assert.Equal(t, 1, version.RollbackOf)
+	assert.Equal(t, "bob", version.Author)
+
+	w = serve(r, "POST", "/order", `{"quantity": 251}`)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+	assert.Equal(t, 3, order.ConfigVersion)
+	assert.Equal(t, 500, order.TotalItems)
+
+	// a rollback without a body is fine too
+	w = serve(r, "POST", "/config/packs/rollback/2", "")
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "rollback to version 2")
+}
+
+func TestConfigHistoryErrors(t *testing.T) {
+	r := httpapi.SetupRouter(setupStore(t, []int{250, 500, 1000}))
+
+	cases := []struct {
+		method, path string
+		status       int
+		code         string
+	}{
+		{"GET", "/config/packs/versions/9", 404, "unknown_version"},
+		{"POST", "/config/packs/rollback/9", 404, "unknown_version"},
+		{"GET", "/config/packs/versions/0", 400, ""},
+		{"GET", "/config/packs/versions/latest", 400, ""},
+		{"GET", "/config/packs/history?sku=SKU-9", 404, "unknown_product"},
+		{"GET", "/config/packs/history?sku=bad%20sku", 400, ""},
+	}
+	for _, tc := range cases {
+		w := serve(r, tc.method, tc.path, "")
+		assert.Equal(t, tc.status, w.Code, tc.path)
+		assert.Contains(t, w.Body.String(), tc.code, tc.path)
+	}
+}
+
+func TestProductConfigHistory(t *testing.T) {
+	r := httpapi.SetupRouter(setupStore(t, []int{250, 500, 1000}))
+
+	serve(r, "POST", "/config/products/SKU-1/packs", `{"pack_sizes": [6, 12]}`)
+	serve(r, "POST", "/config/products/SKU-1/packs", `{"pack_sizes": [6, 12, 24]}`)
+
+	var history httpapi.ConfigHistoryResponse
+	w := serve(r, "GET", "/config/packs/history?sku=SKU-1", "")
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+	assert.Len(t, history.Versions, 2)
+
+	w = serve(r, "POST", "/config/packs/rollback/1?sku=SKU-1", "")
+	assert.Equal(t, 200, w.Code)
+
+	var order httpapi.OrderResponse
+	w = serve(r, "POST", "/order", `{"sku": "SKU-1", "quantity": 24}`)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+	assert.Equal(t, 3, order.ConfigVersion)
+	assert.Len(t, order.Packs, 1)
+	assert.Equal(t, 12, order.Packs[0].Size)
+}
@@ -0,0 +1,355 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rapido-liebre/pack_solver/internal/config"
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+)
+
+// Order objectives: ship the fewest items (then packs), or pay the lowest packaging cost.
+const (
+	ObjectiveItems = "items"
+	ObjectiveCost  = "cost"
+)
+
+// maxAlternatives caps the number of alternatives returned for one order.
+const maxAlternatives = 10
+
+type OrderRequest struct {
+	SKU          string      `json:"sku,omitempty"` // product whose pack configuration applies; global when empty
+	Quantity     int         `json:"quantity" binding:"required"`
+	Strategy     string      `json:"strategy,omitempty"`     // greedy, dp, dfs, bnb or smart (default)
+	Objective    string      `json:"objective,omitempty"`    // items (default) or cost
+	Inventory    map[int]int `json:"inventory,omitempty"`    // available packs per size; unlisted sizes are unlimited
+	Alternatives int         `json:"alternatives,omitempty"` // number of ranked alternatives to return (1-10)
+
+	// Per-size minimum/maximum counts and a cap on the total packs; dp and dfs only
+	Constraints *packsolver.Constraints `json:"constraints,omitempty"`
+
+	// Overage tolerance; when several are set the strictest one applies
+	Exact             bool     `json:"exact,omitempty"`               // ship exactly the quantity
+	MaxOverage        *int     `json:"max_overage,omitempty"`         // at most this many extra items
+	MaxOveragePercent *float64 `json:"max_overage_percent,omitempty"` // at most this percentage of the quantity
+}
+
+type OrderResponse struct {
+	SKU           string                   `json:"sku,omitempty"`
+	Packs         []packsolver.PackResult  `json:"packs"`
+	TotalItems    int                      `json:"total_items"`
+	Strategy      string                   `json:"strategy"`               // strategy that produced the answer
+	Cost          *float64                 `json:"cost,omitempty"`         // packaging cost, when costs are configured
+	Alternatives  []packsolver.Solution    `json:"alternatives,omitempty"` // best distributions ranked by items, then packs
+	Packaging     []packsolver.PackageNode `json:"packaging,omitempty"`    // packs rolled up into cases, pallets, ...; when configured
+	ConfigVersion int                      `json:"config_version"`         // version of the pack configuration the order was solved with
+}
+
+// orderFailure describes an order that could not be solved: the HTTP status and the
+// JSON body POST /order answers with.
+type orderFailure struct {
+	Status int
+	Body   gin.H
+}
+
+func badRequest(msg string) *orderFailure {
+	return &orderFailure{Status: http.StatusBadRequest, Body: gin.H{"error": msg}}
+}
+
+// overageTolerance returns the strictest overage allowed by the request in items,
+// or -1 when the request does not limit the overage.
+func (r OrderRequest) overageTolerance() (int, error) {
+	tolerance := -1
+	tighten := func(n int) {
+		if tolerance < 0 || n < tolerance {
+			tolerance = n
+		}
+	}
+
+	if r.Exact {
+		tighten(0)
+	}
+	if r.MaxOverage != nil {
+		if *r.MaxOverage < 0 {
+			return 0, packsolver.ErrInvalidOverage
+		}
+		tighten(*r.MaxOverage)
+	}
+	if r.MaxOveragePercent != nil {
+		if *r.MaxOveragePercent < 0 {
+			return 0, packsolver.ErrInvalidOverage
+		}
+		tighten(int(math.Floor(float64(r.Quantity) * *r.MaxOveragePercent / 100)))
+	}
+	return tolerance, nil
+}
+
+// normalize validates the request and fills in the default objective and strategy.
+// It does not need the pack configuration, so invalid requests are rejected before
+// the configuration is fetched.
+func (r *OrderRequest) normalize() *orderFailure {
+	if r.Quantity <= 0 {
+		return badRequest("invalid or missing quantity")
+	}
+	if r.SKU != "" && !skuPattern.MatchString(r.SKU) {
+		return badRequest("invalid sku")
+	}
+
+	if r.Objective == "" {
+		r.Objective = ObjectiveItems
+	}
+	if r.Objective != ObjectiveItems && r.Objective != ObjectiveCost {
+		return badRequest("objective must be items or cost")
+	}
+	if r.Objective == ObjectiveCost && r.Inventory != nil {
+		return badRequest("inventory cannot be combined with the cost objective")
+	}
+
+	if r.Alternatives < 0 || r.Alternatives > maxAlternatives {
+		return badRequest("alternatives must be between 1 and 10")
+	}
+	if r.Alternatives > 0 && (r.Objective == ObjectiveCost || r.Inventory != nil || r.Constraints != nil) {
+		return badRequest("alternatives are only ranked by the items objective without inventory or constraints")
+	}
+
+	if r.Constraints != nil {
+		if r.Objective == ObjectiveCost || r.Inventory != nil {
+			return badRequest("constraints cannot be combined with inventory or the cost objective; express stock as constraints.max")
+		}
+		if r.Strategy == "" {
+			r.Strategy = packsolver.StrategyDP
+		}
+		if r.Strategy != packsolver.StrategyDP && r.Strategy != packsolver.StrategyDFS {
+			return badRequest("constraints are only supported by the dp and dfs strategies")
+		}
+	}
+
+	tolerance, err := r.overageTolerance()
+	if err != nil {
+		return badRequest("max_overage and max_overage_percent must be >= 0")
+	}
+
+	if r.Inventory != nil || r.Objective == ObjectiveCost {
+		// Stock limits and costs are only honoured by variants of the DP solver
+		if r.Strategy != "" && r.Strategy != packsolver.StrategyDP {
+			return badRequest("inventory and the cost objective are only supported by the dp strategy")
+		}
+		r.Strategy = packsolver.StrategyDP
+	}
+	if r.Strategy == "" {
+		r.Strategy = packsolver.DefaultStrategy
+	}
+
+	// The tolerance is checked against the answer, which is only conclusive when the
+	// solver minimizes the total
+	if tolerance >= 0 && (r.Strategy == packsolver.StrategyGreedy || r.Objective == ObjectiveCost) {
+		return badRequest("overage limits are not supported by the greedy strategy or the cost objective")
+	}
+
+	if _, ok := packsolver.Lookup(r.Strategy); !ok {
+		return &orderFailure{Status: http.StatusBadRequest, Body: gin.H{
+			"error":            "unknown strategy " + r.Strategy,
+			"valid_strategies": packsolver.Strategies(),
+		}}
+	}
+	return nil
+}
+
+// solveOrder solves a normalized request against the given pack configuration.
+func (a *API) solveOrder(ctx context.Context, req OrderRequest, cfg *config.ConfigVersion) (*OrderResponse, *orderFailure) {
+	sizes, costs := cfg.Sizes, cfg.Costs
+	solver, ok := packsolver.Lookup(req.Strategy)
+	if !ok {
+		return nil, badRequest("unknown strategy " + req.Strategy)
+	}
+	tolerance, err := req.overageTolerance()
+	if err != nil {
+		return nil, solveFailure(err)
+	}
+
+	var packs []packsolver.PackResult
+	var total int
+	switch {
+	case req.Objective == ObjectiveCost:
+		packs, total, _, err = packsolver.SolveMinCostContext(ctx, req.Quantity, sizes, costs)
+	case req.Inventory != nil:
+		packs, total, err = packsolver.SolveWithInventoryContext(ctx, req.Quantity, sizes, req.Inventory)
+	case req.Constraints != nil && req.Strategy == packsolver.StrategyDFS:
+		packs, total, err = packsolver.SolvePackDistribution2ConstrainedContext(ctx, req.Quantity, sizes, *req.Constraints)
+	case req.Constraints != nil:
+		packs, total, err = packsolver.SolvePackDistributionConstrainedContext(ctx, req.Quantity, sizes, *req.Constraints)
+	case req.Strategy == packsolver.StrategyDP || req.Strategy == packsolver.StrategySmart:
+		packs, total, err = a.solveWithTable(ctx, req.SKU, req.Quantity, sizes, solver)
+	default:
+		packs, total, err = solver.Solve(ctx, req.Quantity, sizes)
+	}
+	if err == nil && tolerance >= 0 {
+		err = packsolver.CheckOverage(req.Quantity, total, tolerance)
+	}
+	if err != nil {
+		return nil, solveFailure(err)
+	}
+
+	resp := &OrderResponse{
+		SKU:           req.SKU,
+		Packs:         packs,
+		TotalItems:    total,
+		Strategy:      req.Strategy,
+		ConfigVersion: cfg.Version,
+	}
+	if cost, ok := packsolver.PackCost(packs, costs); ok && len(costs) > 0 {
+		resp.Cost = &cost
+	}
+	if len(cfg.Packaging) > 0 {
+		if resp.Packaging, err = packsolver.RollUp(packs, cfg.Packaging); err != nil {
+			return nil, solveFailure(err)
+		}
+	}
+	if req.Alternatives > 0 {
+		resp.Alternatives, err = packsolver.SolveTopKContext(ctx, req.Quantity, sizes, req.Alternatives)
+		if err != nil {
+			return nil, solveFailure(err)
+		}
+		if tolerance >= 0 {
+			// the overage limit holds for alternatives too, so there may be fewer of them
+			resp.Alternatives = slices.DeleteFunc(resp.Alternatives, func(alt packsolver.Solution) bool {
+				return packsolver.CheckOverage(req.Quantity, alt.TotalItems, tolerance) != nil
+			})
+		}
+	}
+	return resp, nil
+}
+
+// solveWithTable answers from the precomputed table of the sizes, which gives the same
+// optimum as the dp and smart strategies. Sizes whose table would be too large, or a
+// table that could not be built in time, fall back to the solver.
+func (a *API) solveWithTable(ctx context.Context, sku string, quantity int, sizes []int, solver packsolver.Solver) ([]packsolver.PackResult, int, error) {
+	table, err := a.tableCache(sku).Get(ctx, sizes)
+	if err != nil {
+		return solver.Solve(ctx, quantity, sizes)
+	}
+	return table.Solve(quantity)
+}
+
+// @Summary Calculate pack distribution
+// @Description Calculates the optimal pack combination for the requested quantity.
+// @Description The strategy can be chosen in the body or with the strategy query parameter (body wins).
+// @Description An optional inventory map limits how many packs of each size may be used.
+// @Description With objective=cost the cheapest distribution is returned, ties fall back to overage.
+// @Description exact, max_overage and max_overage_percent reject answers shipping too many extra items with 422.
+// @Description constraints limit the packs per size (min, max) and in total (max_packs); they need the dp or dfs strategy.
+// @Description When a packaging hierarchy is configured the packs are also rolled up into it (packaging tree).
+// @Description With a sku the pack configuration of that product is used (404 when it is not configured).
+// @Tags order
+// @Accept json
+// @Produce json
+// @Param request body OrderRequest true "Order quantity"
+// @Param strategy query string false "Solving strategy (greedy, dp, dfs, bnb, smart)"
+// @Param alternatives query int false "Number of ranked alternative distributions to return (1-10)"
+// @Success 200 {object} OrderResponse
+// @Failure 400 {object} map[string]string
+// @Failure 408 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /order [post]
+func (a *API) createOrder(c *gin.Context) {
+	var req OrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Quantity <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid or missing quantity"})
+		return
+	}
+
+	// Query parameters only apply when the body does not set the same option
+	if req.Strategy == "" {
+		req.Strategy = c.Query("strategy")
+	}
+	if v := c.Query("alternatives"); v != "" && req.Alternatives == 0 {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "alternatives must be between 1 and 10"})
+			return
+		}
+		req.Alternatives = n
+	}
+
+	if failure := req.normalize(); failure != nil {
+		c.JSON(failure.Status, failure.Body)
+		return
+	}
+
+	cfg, failure := a.loadPackConfig(c.Request.Context(), req.SKU)
+	if failure != nil {
+		c.JSON(failure.Status, failure.Body)
+		return
+	}
+
+	// The request context is cancelled when the client disconnects, so the solver stops
+	// burning CPU for an answer nobody will read.
+	ctx, cancel := context.WithTimeout(c.Request.Context(), solveTimeout())
+	defer cancel()
+
+	resp, failure := a.solveOrder(ctx, req, cfg)
+	if failure != nil {
+		c.JSON(failure.Status, failure.Body)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// solveFailure maps a solver error to an HTTP status and a machine-readable error code.
+func solveFailure(err error) *orderFailure {
+	var timeoutErr *packsolver.TimeoutError
+	var stockErr *packsolver.InsufficientStockError
+	var overageErr *packsolver.OverageError
+	switch {
+	case errors.As(err, &overageErr):
+		return &orderFailure{Status: http.StatusUnprocessableEntity, Body: gin.H{
+			"error":         err.Error(),
+			"code":          "overage_exceeded",
+			"max_overage":   overageErr.MaxOverage,
+			"closest_total": overageErr.Total,
+		}}
+	case errors.As(err, &stockErr):
+		return &orderFailure{Status: http.StatusUnprocessableEntity, Body: gin.H{
+			"error":     err.Error(),
+			"code":      "insufficient_stock",
+			"available": stockErr.Available,
+			"shortfall": stockErr.Shortfall,
+			"missing":   stockErr.Missing,
+		}}
+	case errors.Is(err, packsolver.ErrInvalidStock):
+		return &orderFailure{Status: http.StatusBadRequest, Body: gin.H{"error": err.Error(), "code": "invalid_stock"}}
+	case errors.Is(err, packsolver.ErrInvalidConstraints):
+		return &orderFailure{Status: http.StatusBadRequest, Body: gin.H{"error": err.Error(), "code": "invalid_constraints"}}
+	case errors.Is(err, packsolver.ErrInvalidOverage):
+		return &orderFailure{Status: http.StatusBadRequest, Body: gin.H{"error": err.Error(), "code": "invalid_overage"}}
+	case errors.Is(err, packsolver.ErrInvalidPackaging):
+		return &orderFailure{Status: http.StatusConflict, Body: gin.H{"error": err.Error(), "code": "invalid_packaging"}}
+	case errors.Is(err, packsolver.ErrInvalidCost):
+		return &orderFailure{Status: http.StatusConflict, Body: gin.H{"error": err.Error(), "code": "missing_cost"}}
+	case errors.Is(err, packsolver.ErrQuantityTooLarge):
+		return &orderFailure{Status: http.StatusBadRequest, Body: gin.H{"error": err.Error(), "code": "quantity_too_large"}}
+	case errors.Is(err, packsolver.ErrInvalidQuantity):
+		return &orderFailure{Status: http.StatusBadRequest, Body: gin.H{"error": err.Error(), "code": "invalid_quantity"}}
+	case errors.Is(err, packsolver.ErrNoPackSizes):
+		return &orderFailure{Status: http.StatusConflict, Body: gin.H{"error": err.Error(), "code": "no_pack_sizes"}}
+	case errors.Is(err, packsolver.ErrUnreachable):
+		return &orderFailure{Status: http.StatusUnprocessableEntity, Body: gin.H{"error": err.Error(), "code": "unreachable"}}
+	case errors.Is(err, packsolver.ErrInvalidSize):
+		return &orderFailure{Status: http.StatusInternalServerError, Body: gin.H{"error": err.Error(), "code": "invalid_pack_size"}}
+	case errors.As(err, &timeoutErr) && errors.Is(err, context.DeadlineExceeded):
+		return &orderFailure{Status: http.StatusGatewayTimeout, Body: gin.H{"error": "solver timed out", "code": "timeout"}}
+	case errors.As(err, &timeoutErr):
+		return &orderFailure{Status: http.StatusRequestTimeout, Body: gin.H{"error": "request cancelled", "code": "cancelled"}}
+	default:
+		return &orderFailure{Status: http.StatusInternalServerError, Body: gin.H{"error": "could not solve order", "code": "solver_error"}}
+	}
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rapido-liebre/pack_solver/internal/config"
+)
+
+// skuPattern restricts SKUs to characters that are safe inside Redis keys and URLs.
+var skuPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+type ProductListResponse struct {
+	Products []string `json:"products"`
+}
+
+// @Summary List configured products
+// @Description Returns the SKUs that have their own pack configuration
+// @Tags config
+// @Produce json
+// @Success 200 {object} ProductListResponse
+// @Failure 500 {object} map[string]string
+// @Router /config/products [get]
+func (a *API) listProducts(c *gin.Context) {
+	skus, err := a.store.ListProducts(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch products"})
+		return
+	}
+	c.JSON(http.StatusOK, ProductListResponse{Products: skus})
+}
+
+// @Summary Get the pack configuration of a product
+// @Description Returns the pack sizes, costs and packaging hierarchy configured for one SKU, with the config version in the ETag header
+// @Tags config
+// @Produce json
+// @Param sku path string true "Product SKU"
+// @Success 200 {object} PackConfigResponse
+// @Header 200 {string} ETag "Config version of the product"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /config/products/{sku}/packs [get]
+func (a *API) getProductPacks(c *gin.Context) {
+	sku := c.Param("sku")
+	if !skuPattern.MatchString(sku) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sku"})
+		return
+	}
+
+	cfg, failure := a.loadPackConfig(c.Request.Context(), sku)
+	if failure != nil {
+		c.JSON(failure.Status, failure.Body)
+		return
+	}
+	c.Header("ETag", versionETag(cfg.Version))
+	c.JSON(http.StatusOK, PackConfigResponse{Success: true, PackSizes: cfg.Sizes, PackCosts: cfg.Costs, Packaging: cfg.Packaging, Version: cfg.Version})
+}
+
+// @Summary Update the pack configuration of a product
+// @Description Sets the pack sizes (and optional costs and packaging) of one SKU, with the same rules as POST /config/packs.
+// @Description Orders naming the SKU are solved with this configuration instead of the global one.
+// @Description If-Match "0" creates the product only if it does not exist yet.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Param sku path string true "Product SKU"
+// @Param request body PackConfigRequest true "Pack sizes"
+// @Param If-Match header string false "ETag of the version the change is based on"
+// @Success 200 {object} PackConfigResponse
+// @Header 200 {string} ETag "Version created by the change"
+// @Failure 400 {object} map[string]string
+// @Failure 412 {object} map[string]any
+// @Failure 500 {object} map[string]string
+// @Router /config/products/{sku}/packs [post]
+func (a *API) setProductPacks(c *gin.Context) {
+	sku := c.Param("sku")
+	if !skuPattern.MatchString(sku) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sku"})
+		return
+	}
+	a.updatePackConfig(c, sku)
+}
+
+// loadPackConfig fetches the configuration orders for the SKU are solved with;
+// an empty SKU selects the global configuration.
+func (a *API) loadPackConfig(ctx context.Context, sku string) (*config.ConfigVersion, *orderFailure) {
+	cfg, err := a.store.GetPackConfig(ctx, sku)
+	if errors.Is(err, config.ErrUnknownProduct) {
+		return nil, &orderFailure{Status: http.StatusNotFound, Body: gin.H{"error": err.Error(), "code": "unknown_product"}}
+	}
+	if err != nil {
+		return nil, &orderFailure{Status: http.StatusInternalServerError, Body: gin.H{"error": "could not fetch pack configuration"}}
+	}
+	return cfg, nil
+}
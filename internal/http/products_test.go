@@ -0,0 +1,98 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpapi "github.com/rapido-liebre/pack_solver/internal/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductPacksConfig(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/config/products/WIDGET/packs", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 404, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown_product")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/config/products/WIDGET/packs", bytes.NewBufferString(`{"pack_sizes": [12, 6, 6, 24]}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/config/products/WIDGET/packs", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	var cfg httpapi.PackConfigResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &cfg))
+	assert.Equal(t, []int{6, 12, 24}, cfg.PackSizes)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/config/products", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	assert.JSONEq(t, `{"products": ["WIDGET"]}`, w.Body.String())
+}
+
+func TestProductPacksInvalid(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	cases := []struct {
+		path string
+		body string
+	}{
+		{path: "/config/products/WIDGET/packs", body: `{"pack_sizes": [0, 6]}`},
+		{path: "/config/products/WIDGET/packs", body: `{}`},
+		{path: "/config/products/bad:sku/packs", body: `{"pack_sizes": [6]}`},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", tc.path, bytes.NewBufferString(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, 400, w.Code, tc.path+" "+tc.body)
+	}
+}
+
+func TestOrderEndpointWithSKU(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/config/products/WIDGET/packs", bytes.NewBufferString(`{"pack_sizes": [6, 12, 24]}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	cases := []struct {
+		body   string
+		status int
+		total  int
+	}{
+		{body: `{"sku": "WIDGET", "quantity": 30}`, status: 200, total: 30},
+		{body: `{"quantity": 30}`, status: 200, total: 250},
+		{body: `{"sku": "GADGET", "quantity": 30}`, status: 404},
+		{body: `{"sku": "bad sku", "quantity": 30}`, status: 400},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/order", bytes.NewBufferString(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, tc.status, w.Code, tc.body)
+		if tc.status == 200 {
+			var resp httpapi.OrderResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tc.total, resp.TotalItems, tc.body)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+)
+
+// maxRecommendQuantities caps the historical quantities accepted by POST /config/packs/recommend.
+const maxRecommendQuantities = 10000
+
+type RecommendRequest struct {
+	Quantities  []int   `json:"quantities" binding:"required"` // historical order quantities
+	Count       int     `json:"count" binding:"required"`      // number of sizes to recommend
+	SizePenalty float64 `json:"size_penalty,omitempty"`        // overage items one extra size must save; Count becomes an upper bound
+	Candidates  []int   `json:"candidates,omitempty"`          // sizes to choose from; the most frequent quantities by default
+	Strategy    string  `json:"strategy,omitempty"`            // solver scoring the candidate sets; bnb by default
+}
+
+// @Summary Recommend pack sizes
+// @Description Suggests the pack sizes that minimize the total overage over a sample of historical order quantities,
+// @Description plus size_penalty for every size when given. The optimizer is a local search that scores each candidate
+// @Description set by solving every quantity of the sample, so the result is a good set rather than a proven optimum.
+// @Description At most 10000 quantities are accepted. Nothing is stored; post the sizes to /config/packs to apply them.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Param request body RecommendRequest true "Historical quantities and number of sizes"
+// @Success 200 {object} packsolver.Recommendation
+// @Failure 400 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /config/packs/recommend [post]
+func recommendPackSizes(c *gin.Context) {
+	var req RecommendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: quantities and count are required"})
+		return
+	}
+	if len(req.Quantities) > maxRecommendQuantities {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "too many quantities", "max_quantities": maxRecommendQuantities})
+		return
+	}
+
+	opts := packsolver.RecommendOptions{Count: req.Count, SizePenalty: req.SizePenalty, Candidates: req.Candidates}
+	if req.Strategy != "" {
+		solver, ok := packsolver.Lookup(req.Strategy)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":            "unknown strategy " + req.Strategy,
+				"valid_strategies": packsolver.Strategies(),
+			})
+			return
+		}
+		opts.Solver = solver
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), solveTimeout())
+	defer cancel()
+
+	rec, err := packsolver.RecommendContext(ctx, req.Quantities, opts)
+	switch {
+	case errors.Is(err, packsolver.ErrInvalidCount), errors.Is(err, packsolver.ErrInvalidPenalty),
+		errors.Is(err, packsolver.ErrInvalidSize):
+		// the candidates come from the request here, not from the stored configuration
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "invalid_options"})
+	case err != nil:
+		failure := solveFailure(err)
+		c.JSON(failure.Status, failure.Body)
+	default:
+		c.JSON(http.StatusOK, rec)
+	}
+}
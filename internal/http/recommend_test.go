@@ -0,0 +1,50 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/config"
+	httpapi "github.com/rapido-liebre/pack_solver/internal/http"
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecommendEndpoint(t *testing.T) {
+	r := httpapi.SetupRouter(config.NewMemoryStore())
+
+	w := httptest.NewRecorder()
+	body := `{"quantities": [250, 250, 500, 1000, 1001], "count": 2, "strategy": "dp"}`
+	req, _ := http.NewRequest("POST", "/config/packs/recommend", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	var resp packsolver.Recommendation
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Sizes, 2)
+	assert.Equal(t, 5, resp.ExactOrders)
+	assert.Equal(t, 0, resp.TotalOverage)
+}
+
+func TestRecommendEndpointInvalid(t *testing.T) {
+	r := httpapi.SetupRouter(config.NewMemoryStore())
+
+	for _, body := range []string{
+		`{"quantities": [250]}`,
+		`{"quantities": [250], "count": -1}`,
+		`{"quantities": [250, 0], "count": 1}`,
+		`{"quantities": [250], "count": 1, "size_penalty": -5}`,
+		`{"quantities": [250], "count": 1, "candidates": [0]}`,
+		`{"quantities": [250], "count": 1, "strategy": "magic"}`,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/config/packs/recommend", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, 400, w.Code, body)
+	}
+}
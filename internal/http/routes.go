@@ -2,12 +2,18 @@
 package http
 
 import (
-	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"context"
+	"errors"
 	"net/http"
+	"os"
+	"slices"
 	"sort"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rapido-liebre/pack_solver/internal/config"
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
 
 	_ "github.com/rapido-liebre/pack_solver/docs"
 	"github.com/swaggo/files"
@@ -15,29 +21,74 @@ import (
 )
 
 type PackConfigRequest struct {
-	PackSizes []int `json:"pack_sizes" binding:"required"`
+	PackSizes []int                       `json:"pack_sizes" binding:"required"`
+	PackCosts map[int]float64             `json:"pack_costs,omitempty"` // unit cost per size; kept unchanged when omitted, {} removes them
+	Packaging []packsolver.PackagingLevel `json:"packaging,omitempty"`  // cases, pallets, ...; kept unchanged when omitted, [] removes it
+	ItemsOnly bool                        `json:"items_only,omitempty"` // only the items shipped matter: warn about sizes that smaller sizes can replace
+	Strict    bool                        `json:"strict,omitempty"`     // reject those sizes instead of warning
+	Author    string                      `json:"author,omitempty"`     // recorded in the config history
+	Comment   string                      `json:"comment,omitempty"`    // recorded in the config history
 }
 
 type PackConfigResponse struct {
-	Success   bool  `json:"success"`
-	PackSizes []int `json:"pack_sizes"`
+	Success   bool                        `json:"success"`
+	PackSizes []int                       `json:"pack_sizes"`
+	PackCosts map[int]float64             `json:"pack_costs,omitempty"`
+	Packaging []packsolver.PackagingLevel `json:"packaging,omitempty"`
+	Warnings  []ConfigWarning             `json:"warnings,omitempty"`
+	Version   int                         `json:"version"` // config version, 0 when never changed
+}
+
+// ConfigWarning points out a size that never lowers the items shipped for any quantity,
+// because smaller sizes add up to it.
+type ConfigWarning struct {
+	Code        string                  `json:"code"` // redundant_multiple or redundant_combination
+	Size        int                     `json:"size"`
+	Message     string                  `json:"message"`
+	Replacement []packsolver.PackResult `json:"replacement"`
+}
+
+// defaultSolveTimeout bounds how long a single order may keep the solver busy
+// when SOLVER_TIMEOUT is not set.
+const defaultSolveTimeout = 10 * time.Second
+
+// solveTimeout returns the solver deadline from SOLVER_TIMEOUT (e.g. "5s"),
+// falling back to defaultSolveTimeout when it is unset or invalid.
+func solveTimeout() time.Duration {
+	if v := os.Getenv("SOLVER_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultSolveTimeout
+}
+
+// API holds what the handlers share: the configuration store and the lookup tables
+// built from it.
+type API struct {
+	store config.ConfigStore
+
+	// tables holds one *packsolver.TableCache per SKU ("" for the global configuration).
+	// A table is rebuilt lazily after its sizes change and invalidated whenever they are stored.
+	tables sync.Map
 }
 
-type OrderRequest struct {
-	Quantity int `json:"quantity" binding:"required"`
+// NewAPI returns the handlers backed by the given configuration store.
+func NewAPI(store config.ConfigStore) *API {
+	return &API{store: store}
 }
 
-type OrderResponse struct {
-	Packs      []packsolver.PackResult `json:"packs"`
-	TotalItems int                     `json:"total_items"`
+func (a *API) tableCache(sku string) *packsolver.TableCache {
+	cache, _ := a.tables.LoadOrStore(sku, &packsolver.TableCache{})
+	return cache.(*packsolver.TableCache)
 }
 
 // SetupRouter initializes the Gin engine with all registered routes.
-// Used by main() and tests to start the API server.
-func SetupRouter() *gin.Engine {
+// Used by tests to start the API server.
+func SetupRouter(store config.ConfigStore) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
-	RegisterRoutes(r)
+	RegisterRoutes(r, store)
 	return r
 }
 
@@ -46,17 +97,38 @@ func SetupRouter() *gin.Engine {
 // - GET /: serve index.html as default
 // - GET /config/packs: returns the current pack size configuration
 // - POST /config/packs: updates the pack size configuration after validation
+// - GET /config/packs/analysis: reports the GCD, Frobenius number and worst-case overage of the sizes
+// - POST /config/packs/recommend: suggests pack sizes for a sample of historical order quantities
+// - GET /config/packs/history: lists every version of the pack configuration, newest first
+// - GET /config/packs/versions/{n}: returns one version of the pack configuration
+// - POST /config/packs/rollback/{n}: restores a version as the new current configuration
+// - GET /config/products: lists the products that have their own pack configuration
+// - GET/POST /config/products/{sku}/packs: reads or updates the pack configuration of one product
 // - POST /order: returns the optimal pack distribution for the requested quantity
-func RegisterRoutes(r *gin.Engine) {
+// - POST /order/verify: checks a proposed pack distribution against the configuration and the optimum
+// - POST /orders/batch: solves many order lines against one configuration snapshot
+func RegisterRoutes(r *gin.Engine, store config.ConfigStore) {
+	a := NewAPI(store)
+
 	// Serve UI from /ui directory
 	r.Static("/static", "./ui")
 	r.GET("/", func(c *gin.Context) {
 		c.File("./ui/index.html")
 	})
 
-	r.GET("/config/packs", getPackSizes)
-	r.POST("/config/packs", setPackSizes)
-	r.POST("/order", createOrder)
+	r.GET("/config/packs", a.getPackSizes)
+	r.POST("/config/packs", a.setPackSizes)
+	r.GET("/config/packs/analysis", a.analyzePackSizes)
+	r.POST("/config/packs/recommend", recommendPackSizes)
+	r.GET("/config/packs/history", a.getPackHistory)
+	r.GET("/config/packs/versions/:n", a.getPackVersion)
+	r.POST("/config/packs/rollback/:n", a.rollbackPackConfig)
+	r.GET("/config/products", a.listProducts)
+	r.GET("/config/products/:sku/packs", a.getProductPacks)
+	r.POST("/config/products/:sku/packs", a.setProductPacks)
+	r.POST("/order", a.createOrder)
+	r.POST("/order/verify", a.verifyOrder)
+	r.POST("/orders/batch", a.createOrderBatch)
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -66,49 +138,218 @@ func RegisterRoutes(r *gin.Engine) {
 }
 
 // @Summary Get current pack size configuration
-// @Description Returns the list of configured pack sizes fetched from Redis
+// @Description Returns the configured pack sizes, costs and packaging. The ETag header holds the config version,
+// @Description to send back in If-Match when updating.
 // @Tags config
 // @Accept json
 // @Produce json
-// @Success 200 {object} map[string][]int
+// @Success 200 {object} PackConfigResponse
+// @Header 200 {string} ETag "Config version, e.g. \"3\""
 // @Failure 500 {object} map[string]string
 // @Router /config/packs [get]
-func getPackSizes(c *gin.Context) {
-	sizes, err := config.GetPackSizes()
+func (a *API) getPackSizes(c *gin.Context) {
+	cfg, err := a.store.GetPackConfig(c.Request.Context(), "")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch pack sizes"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"pack_sizes": sizes})
+	c.Header("ETag", versionETag(cfg.Version))
+	c.JSON(http.StatusOK, gin.H{"pack_sizes": cfg.Sizes, "pack_costs": cfg.Costs, "packaging": cfg.Packaging, "version": cfg.Version})
 }
 
 // @Summary Update pack size configuration
 // @Description Set a new list of pack sizes (must be unique and > 0). It ensures all pack sizes are positive integers, removes duplicates,
-// and sorts the list for consistency and solver optimization
+// and sorts the list for consistency and solver optimization. Optional pack_costs must give a cost >= 0 for every size;
+// when omitted, the current costs are kept and must cover the new sizes, and {} removes them.
+// Optional packaging lists the shipping levels above packs: the first one (e.g. case) gives per_pack counts for every size,
+// further ones (e.g. pallet) a capacity in units of the level below; when omitted, the current packaging is kept and
+// must fit the new sizes, and [] removes it. With items_only=true, sizes that smaller sizes add up to are reported
+// as warnings, or rejected with strict=true. With If-Match set to the ETag of GET /config/packs the change
+// is only stored while that version is current, so concurrent edits fail with 412 instead of overwriting each other.
 // @Tags config
 // @Accept json
 // @Produce json
 // @Param request body PackConfigRequest true "Pack sizes"
+// @Param If-Match header string false "ETag of the version the change is based on"
 // @Success 200 {object} PackConfigResponse
+// @Header 200 {string} ETag "Version created by the change"
 // @Failure 400 {object} map[string]string
+// @Failure 412 {object} map[string]any
 // @Failure 500 {object} map[string]string
 // @Router /config/packs [post]
-func setPackSizes(c *gin.Context) {
+func (a *API) setPackSizes(c *gin.Context) {
+	a.updatePackConfig(c, "")
+}
+
+// updatePackConfig validates the pack configuration in the request body and stores it for
+// the SKU. Costs and packaging that the request omits are kept, and must still fit the new
+// sizes. An If-Match header makes the change conditional on the current version.
+func (a *API) updatePackConfig(c *gin.Context, sku string) {
+	ifVersion, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
 	var req PackConfigRequest
 	if err := c.ShouldBindJSON(&req); err != nil || len(req.PackSizes) == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid or missing pack_sizes array"})
 		return
 	}
+	clean, msg := cleanPackConfig(req)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	warnings, failure := redundancyWarnings(c.Request.Context(), clean, req.ItemsOnly, req.Strict)
+	if failure != nil {
+		c.JSON(failure.Status, failure.Body)
+		return
+	}
 
+	cfg := config.PackConfig{Sizes: clean, Costs: req.PackCosts, Packaging: req.Packaging}
+	if req.PackCosts == nil || req.Packaging == nil || ifVersion != nil {
+		current, err := a.store.GetPackConfig(c.Request.Context(), sku)
+		if err != nil && !errors.Is(err, config.ErrUnknownProduct) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch current config"})
+			return
+		}
+		// what is kept below must come from the version the client edited
+		if ifVersion != nil {
+			currentVersion := 0 // an unknown product has no version yet
+			if current != nil {
+				currentVersion = current.Version
+			}
+			if currentVersion != *ifVersion {
+				failure := conflictFailure(&config.VersionConflictError{Expected: *ifVersion, Current: currentVersion})
+				c.JSON(failure.Status, failure.Body)
+				return
+			}
+		}
+		if current != nil && req.PackCosts == nil {
+			cfg.Costs = current.Costs
+			if msg := checkPackCosts(clean, cfg.Costs); msg != "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "current pack_costs do not fit the new sizes: " + msg + "; send new pack_costs, or {} to remove them"})
+				return
+			}
+		}
+		if current != nil && req.Packaging == nil {
+			cfg.Packaging = current.Packaging
+			if msg := checkPackaging(clean, cfg.Packaging); msg != "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "current packaging does not fit the new sizes: " + msg + "; send new packaging, or [] to remove it"})
+				return
+			}
+		}
+	}
+
+	saved, err := a.store.SetPackConfig(c.Request.Context(), sku, cfg, config.Change{Author: req.Author, Comment: req.Comment, IfVersion: ifVersion})
+	var conflict *config.VersionConflictError
+	if errors.As(err, &conflict) {
+		failure := conflictFailure(conflict)
+		c.JSON(failure.Status, failure.Body)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not store new config"})
+		return
+	}
+	a.tableCache(sku).Invalidate()
+
+	c.Header("ETag", versionETag(saved.Version))
+	c.JSON(http.StatusOK, PackConfigResponse{Success: true, PackSizes: saved.Sizes, PackCosts: saved.Costs, Packaging: saved.Packaging, Warnings: warnings, Version: saved.Version})
+}
+
+// checkPackaging returns why packaging does not fit the sizes: its first level must hold
+// packs of every size and of no other. No packaging at all is fine.
+func checkPackaging(sizes []int, packaging []packsolver.PackagingLevel) string {
+	if len(packaging) == 0 {
+		return ""
+	}
+	if err := packsolver.ValidatePackaging(packaging, sizes); err != nil {
+		return err.Error()
+	}
+	for size := range packaging[0].PerPack {
+		if !slices.Contains(sizes, size) {
+			return "packaging contains a size that is not in pack_sizes"
+		}
+	}
+	return ""
+}
+
+// checkPackCosts returns why costs do not fit the sizes: every size needs a cost >= 0 and
+// no other size may have one. No costs at all is fine.
+func checkPackCosts(sizes []int, costs map[int]float64) string {
+	if len(costs) == 0 {
+		return ""
+	}
+	for s, cost := range costs {
+		if cost < 0 {
+			return "pack costs must be >= 0"
+		}
+		if !slices.Contains(sizes, s) {
+			return "pack_costs contains a size that is not in pack_sizes"
+		}
+	}
+	for _, s := range sizes {
+		if _, ok := costs[s]; !ok {
+			return "pack_costs must contain a cost for every pack size"
+		}
+	}
+	return ""
+}
+
+// redundancyWarnings returns a warning for every size that smaller sizes can replace when
+// only the items shipped matter. In strict mode such sizes reject the configuration instead.
+func redundancyWarnings(parent context.Context, sizes []int, itemsOnly, strict bool) ([]ConfigWarning, *orderFailure) {
+	if !itemsOnly {
+		return nil, nil // every size saves packs for some quantity, so none is redundant
+	}
+	ctx, cancel := context.WithTimeout(parent, solveTimeout())
+	defer cancel()
+
+	found, err := packsolver.FindRedundantSizesContext(ctx, sizes)
+	if err != nil {
+		return nil, solveFailure(err)
+	}
+
+	var warnings []ConfigWarning
+	for _, r := range found {
+		warnings = append(warnings, ConfigWarning{
+			Code:        "redundant_" + r.Kind,
+			Size:        r.Size,
+			Message:     r.Reason(),
+			Replacement: r.Replacement,
+		})
+	}
+	if strict && len(warnings) > 0 {
+		return nil, &orderFailure{Status: http.StatusBadRequest, Body: gin.H{
+			"error":    "pack sizes contain sizes that smaller sizes can replace",
+			"code":     "redundant_sizes",
+			"warnings": warnings,
+		}}
+	}
+	return warnings, nil
+}
+
+// cleanPackConfig validates a pack configuration and returns its sizes deduplicated and
+// sorted, or a message describing why the configuration was rejected.
+func cleanPackConfig(req PackConfigRequest) ([]int, string) {
 	// Validation: all pack sizes must be > 0
 	// This loop checks that every provided pack size is a positive integer
 	for _, s := range req.PackSizes {
 		if s <= 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "pack sizes must be > 0"})
-			return
+			return nil, "pack sizes must be > 0"
 		}
 	}
 
+	// Costs are optional, but when given every size needs one and none may be negative
+	if msg := checkPackCosts(req.PackSizes, req.PackCosts); msg != "" {
+		return nil, msg
+	}
+
+	// A packaging hierarchy, when given, must hold packs of every size; an empty one removes it
+	if msg := checkPackaging(req.PackSizes, req.Packaging); msg != "" {
+		return nil, msg
+	}
+
 	// Remove duplicates and sort ascending
 	sizeMap := map[int]struct{}{}
 	for _, s := range req.PackSizes {
@@ -121,41 +362,5 @@ func setPackSizes(c *gin.Context) {
 
 	// Sort the pack sizes in ascending order for consistency and optimization in the solver algorithm
 	sort.Ints(clean)
-
-	if err := config.SetPackSizes(clean); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not store new config"})
-		return
-	}
-
-	c.JSON(http.StatusOK, PackConfigResponse{Success: true, PackSizes: clean})
-}
-
-// @Summary Calculate pack distribution
-// @Description Calculates the optimal pack combination for the requested quantity
-// @Tags order
-// @Accept json
-// @Produce json
-// @Param request body OrderRequest true "Order quantity"
-// @Success 200 {object} OrderResponse
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
-// @Router /order [post]
-func createOrder(c *gin.Context) {
-	var req OrderRequest
-	if err := c.ShouldBindJSON(&req); err != nil || req.Quantity <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid or missing quantity"})
-		return
-	}
-
-	sizes, err := config.GetPackSizes()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch pack sizes"})
-		return
-	}
-
-	packs, total := packsolver.SolveSmart(req.Quantity, sizes)
-	c.JSON(http.StatusOK, OrderResponse{
-		Packs:      packs,
-		TotalItems: total,
-	})
+	return clean, ""
 }
@@ -3,16 +3,43 @@ package http_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/alicebob/miniredis/v2"
+	"github.com/rapido-liebre/pack_solver/internal/config"
 	httpapi "github.com/rapido-liebre/pack_solver/internal/http"
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
 	"github.com/stretchr/testify/assert"
 )
 
+// setupStore returns an in-memory store holding the given pack sizes.
+func setupStore(t *testing.T, sizes []int) config.ConfigStore {
+	store := config.NewMemoryStore()
+	_, err := store.SetPackConfig(context.Background(), "", config.PackConfig{Sizes: sizes}, config.Change{})
+	assert.NoError(t, err)
+	return store
+}
+
+// setupMockRedis starts miniredis and returns a Redis store on it holding the given pack sizes.
+func setupMockRedis(t *testing.T, sizes []int) (*miniredis.Miniredis, config.ConfigStore) {
+	s, err := miniredis.Run()
+	assert.NoError(t, err)
+	t.Cleanup(s.Close)
+
+	store, err := config.NewRedisStore(s.Addr())
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	_, err = store.SetPackConfig(context.Background(), "", config.PackConfig{Sizes: sizes}, config.Change{})
+	assert.NoError(t, err)
+	return s, store
+}
+
 func TestHealthEndpoint(t *testing.T) {
-	r := httpapi.SetupRouter()
+	r := httpapi.SetupRouter(config.NewMemoryStore())
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/health", nil)
 	r.ServeHTTP(w, req)
@@ -21,7 +48,7 @@ func TestHealthEndpoint(t *testing.T) {
 }
 
 func TestOrderEndpointInvalidInput(t *testing.T) {
-	r := httpapi.SetupRouter()
+	r := httpapi.SetupRouter(config.NewMemoryStore())
 	w := httptest.NewRecorder()
 	body := []byte(`{"quantity": -5}`)
 	req, _ := http.NewRequest("POST", "/order", bytes.NewBuffer(body))
@@ -31,7 +58,7 @@ func TestOrderEndpointInvalidInput(t *testing.T) {
 }
 
 func TestConfigPacksInvalidPayload(t *testing.T) {
-	r := httpapi.SetupRouter()
+	r := httpapi.SetupRouter(config.NewMemoryStore())
 	w := httptest.NewRecorder()
 	body := []byte(`{"pack_sizes": [0, -1, 1000]}`)
 	req, _ := http.NewRequest("POST", "/config/packs", bytes.NewBuffer(body))
@@ -41,7 +68,7 @@ func TestConfigPacksInvalidPayload(t *testing.T) {
 }
 
 func TestConfigPacksMissingField(t *testing.T) {
-	r := httpapi.SetupRouter()
+	r := httpapi.SetupRouter(config.NewMemoryStore())
 	w := httptest.NewRecorder()
 	body := []byte(`{}`)
 	req, _ := http.NewRequest("POST", "/config/packs", bytes.NewBuffer(body))
@@ -49,3 +76,530 @@ func TestConfigPacksMissingField(t *testing.T) {
 	r.ServeHTTP(w, req)
 	assert.Equal(t, 400, w.Code)
 }
+
+func TestOrderEndpointUnknownStrategy(t *testing.T) {
+	r := httpapi.SetupRouter(config.NewMemoryStore())
+	w := httptest.NewRecorder()
+	body := []byte(`{"quantity": 100, "strategy": "quantum"}`)
+	req, _ := http.NewRequest("POST", "/order", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "valid_strategies")
+	assert.Contains(t, w.Body.String(), "greedy")
+}
+
+func TestOrderEndpointUnknownStrategyQuery(t *testing.T) {
+	r := httpapi.SetupRouter(config.NewMemoryStore())
+	w := httptest.NewRecorder()
+	body := []byte(`{"quantity": 100}`)
+	req, _ := http.NewRequest("POST", "/order?strategy=quantum", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "quantum")
+}
+
+func TestOrderEndpointEchoesStrategy(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	for _, strategy := range []string{"greedy", "dp", "dfs", "bnb", "smart"} {
+		w := httptest.NewRecorder()
+		body := []byte(`{"quantity": 1250, "strategy": "` + strategy + `"}`)
+		req, _ := http.NewRequest("POST", "/order", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, 200, w.Code)
+
+		var resp httpapi.OrderResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, strategy, resp.Strategy)
+		assert.Equal(t, 1250, resp.TotalItems)
+	}
+
+	// without a strategy the default one is reported
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/order", bytes.NewBufferString(`{"quantity": 1250}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"strategy":"smart"`)
+}
+
+func TestOrderEndpointSolverTimeout(t *testing.T) {
+	store := setupStore(t, []int{1, 2, 3, 5, 7})
+	t.Setenv("SOLVER_TIMEOUT", "20ms")
+	r := httpapi.SetupRouter(store)
+
+	w := httptest.NewRecorder()
+	body := []byte(`{"quantity": 100000, "strategy": "dfs"}`)
+	req, _ := http.NewRequest("POST", "/order", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+}
+
+func TestOrderEndpointSolverErrors(t *testing.T) {
+	cases := []struct {
+		stored string
+		status int
+		code   string
+	}{
+		{stored: `[]`, status: http.StatusConflict, code: "no_pack_sizes"},
+		{stored: `[250, 0]`, status: http.StatusInternalServerError, code: "invalid_pack_size"},
+	}
+
+	for _, tc := range cases {
+		s, store := setupMockRedis(t, []int{250})
+		assert.NoError(t, s.Set(config.PackSizesKey, tc.stored))
+		r := httpapi.SetupRouter(store)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/order", bytes.NewBufferString(`{"quantity": 100}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, tc.status, w.Code, tc.stored)
+		assert.Contains(t, w.Body.String(), tc.code, tc.stored)
+	}
+}
+
+func TestOrderEndpointWithInventory(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	w := httptest.NewRecorder()
+	body := []byte(`{"quantity": 3000, "inventory": {"250": 10, "500": 10, "1000": 1}}`)
+	req, _ := http.NewRequest("POST", "/order", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	var resp httpapi.OrderResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 3000, resp.TotalItems)
+	assert.Equal(t, "dp", resp.Strategy)
+	for _, p := range resp.Packs {
+		if p.Size == 1000 {
+			assert.Equal(t, 1, p.Count)
+		}
+	}
+}
+
+func TestOrderEndpointInsufficientStock(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	w := httptest.NewRecorder()
+	body := []byte(`{"quantity": 5000, "inventory": {"250": 1, "500": 1, "1000": 2}}`)
+	req, _ := http.NewRequest("POST", "/order", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "insufficient_stock")
+	assert.Contains(t, w.Body.String(), `"shortfall":2250`)
+}
+
+func TestOrderEndpointInventoryWithOtherStrategy(t *testing.T) {
+	r := httpapi.SetupRouter(config.NewMemoryStore())
+	w := httptest.NewRecorder()
+	body := []byte(`{"quantity": 100, "strategy": "greedy", "inventory": {"250": 1}}`)
+	req, _ := http.NewRequest("POST", "/order", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+}
+
+func TestConfigPacksInvalidCosts(t *testing.T) {
+	r := httpapi.SetupRouter(config.NewMemoryStore())
+
+	for _, body := range []string{
+		`{"pack_sizes": [250, 500], "pack_costs": {"250": 1.0}}`,
+		`{"pack_sizes": [250, 500], "pack_costs": {"250": 1.0, "500": -1}}`,
+		`{"pack_sizes": [250, 500], "pack_costs": {"250": 1.0, "500": 2.0, "1000": 3.0}}`,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/config/packs", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, 400, w.Code, body)
+	}
+}
+
+func TestConfigPacksKeptCosts(t *testing.T) {
+	r := httpapi.SetupRouter(config.NewMemoryStore())
+	w := serve(r, "POST", "/config/packs", `{"pack_sizes": [250, 500], "pack_costs": {"250": 1.0, "500": 1.5}}`)
+	assert.Equal(t, 200, w.Code)
+
+	// the kept costs are echoed, as they are what got stored
+	w = serve(r, "POST", "/config/packs", `{"pack_sizes": [500, 250]}`)
+	assert.Equal(t, 200, w.Code)
+	var resp httpapi.PackConfigResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, map[int]float64{250: 1.0, 500: 1.5}, resp.PackCosts)
+
+	// kept costs must still cover every size
+	for _, body := range []string{
+		`{"pack_sizes": [250, 500, 1000]}`,
+		`{"pack_sizes": [250]}`,
+	} {
+		w = serve(r, "POST", "/config/packs", body)
+		assert.Equal(t, 400, w.Code, body)
+		assert.Contains(t, w.Body.String(), "current pack_costs do not fit the new sizes", body)
+	}
+	w = serve(r, "GET", "/config/packs", "")
+	assert.Contains(t, w.Body.String(), `"pack_sizes":[250,500]`)
+
+	// {} removes them
+	w = serve(r, "POST", "/config/packs", `{"pack_sizes": [250, 500, 1000], "pack_costs": {}}`)
+	assert.Equal(t, 200, w.Code)
+	assert.NotContains(t, w.Body.String(), "pack_costs")
+	w = serve(r, "POST", "/order", `{"quantity": 1000, "objective": "cost"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "missing_cost")
+}
+
+func TestConfigPacksKeptPackaging(t *testing.T) {
+	r := httpapi.SetupRouter(config.NewMemoryStore())
+	w := serve(r, "POST", "/config/packs", `{"pack_sizes": [250, 500], "packaging": [{"name": "case", "per_pack": {"250": 24, "500": 12}}]}`)
+	assert.Equal(t, 200, w.Code)
+
+	// the kept packaging is echoed, as it is what got stored
+	w = serve(r, "POST", "/config/packs", `{"pack_sizes": [500, 250]}`)
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"packaging":[{"name":"case"`)
+
+	// kept packaging must still hold every size and no other
+	for _, body := range []string{
+		`{"pack_sizes": [250, 500, 1000]}`,
+		`{"pack_sizes": [250]}`,
+	} {
+		w = serve(r, "POST", "/config/packs", body)
+		assert.Equal(t, 400, w.Code, body)
+		assert.Contains(t, w.Body.String(), "current packaging does not fit the new sizes", body)
+	}
+	w = serve(r, "POST", "/order", `{"quantity": 1000}`)
+	assert.Equal(t, 200, w.Code)
+
+	// [] removes it
+	w = serve(r, "POST", "/config/packs", `{"pack_sizes": [250, 500, 1000], "packaging": []}`)
+	assert.Equal(t, 200, w.Code)
+	assert.NotContains(t, w.Body.String(), "packaging")
+	w = serve(r, "POST", "/order", `{"quantity": 1000}`)
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestOrderEndpointCostObjective(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	w := httptest.NewRecorder()
+	body := []byte(`{"pack_sizes": [250, 500, 1000], "pack_costs": {"250": 1.0, "500": 1.5, "1000": 4.0}}`)
+	req, _ := http.NewRequest("POST", "/config/packs", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/order", bytes.NewBufferString(`{"quantity": 2000, "objective": "cost"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	var resp httpapi.OrderResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2000, resp.TotalItems)
+	assert.Equal(t, []packsolver.PackResult{{Size: 500, Count: 4}}, resp.Packs)
+	if assert.NotNil(t, resp.Cost) {
+		assert.InDelta(t, 6.0, *resp.Cost, 1e-9)
+	}
+
+	// the default objective still reports the cost of its answer
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/order", bytes.NewBufferString(`{"quantity": 2000}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"cost":8`)
+}
+
+func TestOrderEndpointAlternatives(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/order?alternatives=3", bytes.NewBufferString(`{"quantity": 1000}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	var resp httpapi.OrderResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Alternatives, 3)
+	assert.Equal(t, []packsolver.PackResult{{Size: 1000, Count: 1}}, resp.Alternatives[0].Packs)
+	assert.Equal(t, []packsolver.PackResult{{Size: 500, Count: 2}}, resp.Alternatives[1].Packs)
+	assert.Equal(t, 3, resp.Alternatives[2].TotalPacks)
+
+	for _, q := range []string{"0", "11", "abc"} {
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("POST", "/order?alternatives="+q, bytes.NewBufferString(`{"quantity": 1000}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, 400, w.Code, q)
+	}
+
+	// too large to rank with sizes sharing no common divisor
+	store = setupStore(t, []int{23, 31, 53})
+	r = httpapi.SetupRouter(store)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/order?alternatives=3", bytes.NewBufferString(`{"quantity": 10000000}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "quantity_too_large")
+}
+
+func TestOrderEndpointOverageTolerance(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	cases := []struct {
+		body   string
+		status int
+	}{
+		{body: `{"quantity": 1750, "exact": true}`, status: 200},
+		{body: `{"quantity": 1700, "exact": true}`, status: 422},
+		{body: `{"quantity": 1700, "max_overage": 50}`, status: 200},
+		{body: `{"quantity": 1700, "max_overage": 49}`, status: 422},
+		{body: `{"quantity": 1700, "max_overage_percent": 3}`, status: 200},
+		{body: `{"quantity": 1700, "max_overage_percent": 2.9}`, status: 422},
+		{body: `{"quantity": 1700, "max_overage": 100, "exact": true}`, status: 422},
+		{body: `{"quantity": 1700, "max_overage": -1}`, status: 400},
+		{body: `{"quantity": 1700, "exact": true, "strategy": "greedy"}`, status: 400},
+	}
+
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/order", bytes.NewBufferString(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, tc.status, w.Code, tc.body)
+		if tc.status == 422 {
+			assert.Contains(t, w.Body.String(), "overage_exceeded", tc.body)
+			assert.Contains(t, w.Body.String(), `"closest_total":1750`, tc.body)
+		}
+	}
+}
+
+func TestOrderEndpointOverageToleranceAlternatives(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	cases := []struct {
+		body   string
+		totals []int
+	}{
+		{body: `{"quantity": 500, "exact": true, "alternatives": 3}`, totals: []int{500, 500}},
+		{body: `{"quantity": 400, "max_overage": 100, "alternatives": 3}`, totals: []int{500, 500}},
+		{body: `{"quantity": 500, "alternatives": 3}`, totals: []int{500, 500, 1000}},
+	}
+
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/order", bytes.NewBufferString(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, 200, w.Code, tc.body)
+
+		var resp httpapi.OrderResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		var totals []int
+		for _, alt := range resp.Alternatives {
+			totals = append(totals, alt.TotalItems)
+		}
+		assert.Equal(t, tc.totals, totals, tc.body)
+	}
+}
+
+func TestOrderEndpointUsesNewSizesAfterConfigChange(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	order := func() string {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/order", bytes.NewBufferString(`{"quantity": 1000000}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, 200, w.Code)
+		return w.Body.String()
+	}
+	assert.Contains(t, order(), `{"size":1000,"count":1000}`)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/config/packs", bytes.NewBufferString(`{"pack_sizes": [23, 31, 53]}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	body := order()
+	assert.Contains(t, body, `"total_items":1000000`)
+	assert.Contains(t, body, `"size":53`)
+}
+
+func TestOrderEndpointDefaultPackSizes(t *testing.T) {
+	// a fresh store answers with the default sizes instead of failing
+	store := config.WithDefaults(config.NewMemoryStore(), config.PackConfig{Sizes: []int{250, 500, 1000}})
+	r := httpapi.SetupRouter(store)
+
+	w := serve(r, "GET", "/config/packs", "")
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"pack_sizes":[250,500,1000]`)
+
+	w = serve(r, "POST", "/order", `{"quantity": 251}`)
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"total_items":500`)
+}
+
+func TestOrderEndpointPackaging(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	w := httptest.NewRecorder()
+	body := `{
+		"pack_sizes": [250, 500, 1000],
+		"packaging": [
+			{"name": "case", "per_pack": {"250": 24, "500": 12, "1000": 6}},
+			{"name": "pallet", "capacity": 40}
+		]
+	}`
+	req, _ := http.NewRequest("POST", "/config/packs", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/order", bytes.NewBufferString(`{"quantity": 250000}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	var resp httpapi.OrderResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	// 250 packs of 1000: 41 full cases and one with 4 packs, on one full pallet and one with 2 cases
+	assert.Equal(t, []packsolver.PackageNode{
+		{Level: "pallet", Count: 1, Items: 240000, Contents: []packsolver.PackageNode{
+			{Level: "case", Count: 40, Items: 6000, Contents: []packsolver.PackageNode{
+				{Level: "pack", Size: 1000, Count: 6, Items: 1000},
+			}},
+		}},
+		{Level: "pallet", Count: 1, Items: 10000, Contents: []packsolver.PackageNode{
+			{Level: "case", Count: 1, Items: 6000, Contents: []packsolver.PackageNode{
+				{Level: "pack", Size: 1000, Count: 6, Items: 1000},
+			}},
+			{Level: "case", Count: 1, Items: 4000, Contents: []packsolver.PackageNode{
+				{Level: "pack", Size: 1000, Count: 4, Items: 1000},
+			}},
+		}},
+	}, resp.Packaging)
+}
+
+func TestConfigPacksInvalidPackaging(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	for _, packaging := range []string{
+		`[{"name": "case", "per_pack": {"250": 24, "500": 12}}]`,
+		`[{"name": "case", "per_pack": {"250": 24, "500": 12, "1000": 6, "2000": 3}}]`,
+		`[{"name": "case", "per_pack": {"250": 24, "500": 12, "1000": 6}}, {"name": "pallet"}]`,
+	} {
+		w := httptest.NewRecorder()
+		body := `{"pack_sizes": [250, 500, 1000], "packaging": ` + packaging + `}`
+		req, _ := http.NewRequest("POST", "/config/packs", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, 400, w.Code, packaging)
+	}
+}
+
+func TestOrderEndpointConstraints(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	cases := []struct {
+		body   string
+		status int
+		total  int
+	}{
+		// 750 is normally 500+250; with no 500-packs it becomes 3x250
+		{body: `{"quantity": 750, "constraints": {"max": {"500": 0}}}`, status: 200, total: 750},
+		{body: `{"quantity": 750, "strategy": "dfs", "constraints": {"max": {"500": 0}}}`, status: 200, total: 750},
+		{body: `{"quantity": 750, "constraints": {"max": {"500": 0}, "max_packs": 2}}`, status: 200, total: 1000},
+		{body: `{"quantity": 300, "constraints": {"min": {"1000": 1}}}`, status: 200, total: 1000},
+		{body: `{"quantity": 3000, "constraints": {"max": {"250": 1, "500": 1, "1000": 1}}}`, status: 422},
+		{body: `{"quantity": 750, "constraints": {"min": {"300": 1}}}`, status: 400},
+		{body: `{"quantity": 750, "strategy": "greedy", "constraints": {"max": {"500": 0}}}`, status: 400},
+		{body: `{"quantity": 750, "inventory": {"500": 1}, "constraints": {"max": {"500": 0}}}`, status: 400},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/order", bytes.NewBufferString(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, tc.status, w.Code, tc.body)
+		if tc.status == 200 {
+			var resp httpapi.OrderResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tc.total, resp.TotalItems, tc.body)
+		}
+	}
+}
+
+func TestConfigPacksRedundantSizes(t *testing.T) {
+	store := setupStore(t, []int{250})
+	r := httpapi.SetupRouter(store)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/config/packs", bytes.NewBufferString(`{"pack_sizes": [6, 9, 15, 18, 20], "items_only": true}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	var resp httpapi.PackConfigResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	if assert.Len(t, resp.Warnings, 2) {
+		assert.Equal(t, "redundant_combination", resp.Warnings[0].Code)
+		assert.Equal(t, 15, resp.Warnings[0].Size)
+		assert.Equal(t, "redundant_multiple", resp.Warnings[1].Code)
+		assert.Equal(t, 18, resp.Warnings[1].Size)
+		assert.Equal(t, []packsolver.PackResult{{Size: 9, Count: 2}}, resp.Warnings[1].Replacement)
+	}
+
+	// strict mode rejects the same sizes and keeps the stored configuration
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/config/packs", bytes.NewBufferString(`{"pack_sizes": [6, 9, 20, 40], "items_only": true, "strict": true}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "redundant_sizes")
+
+	cfg, err := store.GetPackConfig(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{6, 9, 15, 18, 20}, cfg.Sizes)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/config/packs", bytes.NewBufferString(`{"pack_sizes": [23, 31, 53], "items_only": true, "strict": true}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	assert.NotContains(t, w.Body.String(), "warnings")
+
+	// every size saves packs, so by default none is reported, not even for the default sizes
+	for _, body := range []string{
+		`{"pack_sizes": [6, 9, 15, 18, 20]}`,
+		`{"pack_sizes": [250, 500, 1000, 2000, 5000], "strict": true}`,
+	} {
+		w = serve(r, "POST", "/config/packs", body)
+		assert.Equal(t, 200, w.Code, body)
+		assert.NotContains(t, w.Body.String(), "warnings", body)
+	}
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+)
+
+type VerifyRequest struct {
+	SKU      string                  `json:"sku,omitempty"` // product whose pack configuration applies; global when empty
+	Quantity int                     `json:"quantity" binding:"required"`
+	Packs    []packsolver.PackResult `json:"packs"` // proposed distribution
+}
+
+// @Summary Verify a proposed pack distribution
+// @Description Checks that every proposed size is configured and that the packs cover the quantity, and compares
+// @Description the proposal with the optimum (extra_items, extra_packs). An invalid proposal is still answered with 200
+// @Description and valid=false; 400 is reserved for malformed requests.
+// @Tags order
+// @Accept json
+// @Produce json
+// @Param request body VerifyRequest true "Quantity and proposed packs"
+// @Success 200 {object} packsolver.Verification
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /order/verify [post]
+func (a *API) verifyOrder(c *gin.Context) {
+	var req VerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Quantity <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid or missing quantity"})
+		return
+	}
+	if req.SKU != "" && !skuPattern.MatchString(req.SKU) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sku"})
+		return
+	}
+
+	cfg, failure := a.loadPackConfig(c.Request.Context(), req.SKU)
+	if failure != nil {
+		c.JSON(failure.Status, failure.Body)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), solveTimeout())
+	defer cancel()
+
+	v, err := packsolver.VerifyContext(ctx, req.Quantity, cfg.Sizes, req.Packs)
+	if errors.Is(err, packsolver.ErrInvalidProposal) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "invalid_proposal"})
+		return
+	}
+	if err != nil {
+		failure := solveFailure(err)
+		c.JSON(failure.Status, failure.Body)
+		return
+	}
+	c.JSON(http.StatusOK, v)
+}
@@ -0,0 +1,60 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpapi "github.com/rapido-liebre/pack_solver/internal/http"
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyEndpoint(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	w := httptest.NewRecorder()
+	body := `{"quantity": 1201, "packs": [{"size": 500, "count": 2}, {"size": 250, "count": 2}]}`
+	req, _ := http.NewRequest("POST", "/order/verify", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	var v packsolver.Verification
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &v))
+	assert.True(t, v.Valid)
+	assert.False(t, v.IsOptimal)
+	assert.Equal(t, 1500, v.TotalItems)
+	assert.Equal(t, 250, v.ExtraItems)
+	assert.Equal(t, 2, v.ExtraPacks)
+	assert.Equal(t, 1250, v.Optimal.TotalItems)
+}
+
+func TestVerifyEndpointInvalidProposal(t *testing.T) {
+	store := setupStore(t, []int{250, 500, 1000})
+	r := httpapi.SetupRouter(store)
+
+	cases := []struct {
+		body   string
+		status int
+	}{
+		{body: `{"quantity": 1201, "packs": [{"size": 300, "count": 5}]}`, status: 200},
+		{body: `{"quantity": 1201, "packs": [{"size": 1000, "count": 1}]}`, status: 200},
+		{body: `{"quantity": 1201, "packs": [{"size": 1000, "count": -1}]}`, status: 400},
+		{body: `{"packs": [{"size": 1000, "count": 2}]}`, status: 400},
+		{body: `{"sku": "GADGET", "quantity": 1, "packs": []}`, status: 404},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/order/verify", bytes.NewBufferString(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, tc.status, w.Code, tc.body)
+		if tc.status == 200 {
+			assert.Contains(t, w.Body.String(), `"valid":false`, tc.body)
+		}
+	}
+}
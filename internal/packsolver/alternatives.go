@@ -0,0 +1,194 @@
+package packsolver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrInvalidK is returned when fewer than one alternative is requested.
+var ErrInvalidK = errors.New("number of alternatives must be > 0")
+
+// ErrQuantityTooLarge is returned by SolveTopK when the table of fewest packs per total
+// would exceed maxTopKEntries for the quantity and sizes.
+var ErrQuantityTooLarge = errors.New("quantity too large to rank alternatives for these pack sizes")
+
+// maxTopKEntries caps the totals × sizes entries of the SolveTopK table (4 bytes each).
+const maxTopKEntries = 1 << 23
+
+// Solution is one complete pack distribution together with its objective values.
+type Solution struct {
+	Packs      []PackResult `json:"packs"`
+	TotalItems int          `json:"total_items"`
+	TotalPacks int          `json:"total_packs"`
+}
+
+// SolveTopK returns up to k distinct pack distributions covering the quantity, ranked by
+// the same objective as SolvePackDistribution: fewest items, then fewest packs.
+// Only distributions in which every pack is needed are considered, i.e. dropping any
+// single pack would leave the quantity uncovered, so the ranking never pads the list
+// with an optimum plus a spare pack.
+func SolveTopK(quantity int, sizes []int, k int) ([]Solution, error) {
+	return SolveTopKContext(context.Background(), quantity, sizes, k)
+}
+
+// SolveTopKContext is SolveTopK that stops with a *TimeoutError once ctx is done.
+//
+// It builds a table of the fewest packs needed to reach every total from each suffix of
+// the sizes (largest first), which takes O(len(sizes) × quantity / g) memory with g the
+// greatest common divisor of the sizes, as every shippable total is a multiple of g; a
+// table above maxTopKEntries yields ErrQuantityTooLarge. Totals are then visited in
+// increasing order and the distributions reaching each one are enumerated depth-first,
+// pruning every branch that cannot beat the worst of the candidates kept.
+func SolveTopKContext(ctx context.Context, quantity int, sizes []int, k int) ([]Solution, error) {
+	if err := validate(quantity, sizes); err != nil {
+		return nil, err
+	}
+	if k <= 0 {
+		return nil, ErrInvalidK
+	}
+
+	// work in multiples of g: reduced total t covers the quantity iff t >= ceil(quantity/g)
+	desc := uniqueSizes(sizes)
+	g := desc[0]
+	for _, size := range desc[1:] {
+		g = gcd(g, size)
+	}
+	for j := range desc {
+		desc[j] /= g
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(desc)))
+	n := len(desc)
+	quantity = (quantity + g - 1) / g
+	limit := quantity + desc[0] - 1 // a larger total always contains a pack that can be dropped
+	if int64(n+1)*int64(limit+1) > maxTopKEntries {
+		return nil, ErrQuantityTooLarge
+	}
+
+	// minPacks[j][r] = fewest packs of desc[j:] summing exactly to r
+	const inf = math.MaxInt32
+	minPacks := make([][]int32, n+1)
+	minPacks[n] = make([]int32, limit+1)
+	for r := 1; r <= limit; r++ {
+		minPacks[n][r] = inf
+	}
+	steps := 0
+	for j := n - 1; j >= 0; j-- {
+		row, next := make([]int32, limit+1), minPacks[j+1]
+		for r := 0; r <= limit; r++ {
+			steps++
+			if steps%cancelCheckInterval == 0 {
+				if err := ctx.Err(); err != nil {
+					return nil, &TimeoutError{Err: err}
+				}
+			}
+			row[r] = next[r]
+			if r >= desc[j] && row[r-desc[j]] != inf && row[r-desc[j]]+1 < row[r] {
+				row[r] = row[r-desc[j]] + 1
+			}
+		}
+		minPacks[j] = row
+	}
+
+	var found []Solution
+	counts := make([]int, n)
+	var ctxErr error
+
+	for total := quantity; total <= limit && len(found) < k; total++ {
+		if minPacks[0][total] == inf {
+			continue
+		}
+
+		// best holds the fewest-pack distributions reaching total, sorted by pack count
+		var best []Solution
+		want := k - len(found)
+
+		var recurse func(j, remaining, used int)
+		recurse = func(j, remaining, used int) {
+			if ctxErr != nil {
+				return
+			}
+			steps++
+			if steps%cancelCheckInterval == 0 {
+				if ctxErr = ctx.Err(); ctxErr != nil {
+					return
+				}
+			}
+
+			if j == n {
+				if remaining == 0 && needsEveryPack(quantity, total, desc, counts) {
+					best = insertSolution(best, toSolution(total, used, g, desc, counts, sizes), want)
+				}
+				return
+			}
+			for c := remaining / desc[j]; c >= 0; c-- {
+				rest := remaining - c*desc[j]
+				if minPacks[j+1][rest] == inf {
+					continue
+				}
+				if len(best) == want && used+c+int(minPacks[j+1][rest]) >= best[want-1].TotalPacks {
+					continue
+				}
+				counts[j] = c
+				recurse(j+1, rest, used+c)
+				counts[j] = 0
+			}
+		}
+		recurse(0, total, 0)
+
+		if ctxErr != nil {
+			return nil, &TimeoutError{Err: ctxErr}
+		}
+		found = append(found, best...)
+	}
+
+	if len(found) == 0 {
+		return nil, ErrUnreachable
+	}
+	return found, nil
+}
+
+// needsEveryPack reports whether dropping the smallest pack used would leave the quantity uncovered.
+func needsEveryPack(quantity, total int, desc, counts []int) bool {
+	for j := len(desc) - 1; j >= 0; j-- {
+		if counts[j] > 0 {
+			return total-desc[j] < quantity
+		}
+	}
+	return false
+}
+
+// toSolution converts per-size counts of the sizes reduced by g into a Solution listing
+// sizes in the caller's order.
+func toSolution(total, packs, g int, desc, counts, sizes []int) Solution {
+	bySize := make(map[int]int, len(desc))
+	for j, c := range counts {
+		bySize[desc[j]*g] = c
+	}
+
+	sol := Solution{TotalItems: total * g, TotalPacks: packs}
+	for _, size := range sizes {
+		if c := bySize[size]; c > 0 {
+			sol.Packs = append(sol.Packs, PackResult{Size: size, Count: c})
+			delete(bySize, size) // duplicated sizes are listed once
+		}
+	}
+	return sol
+}
+
+// insertSolution adds sol to the list sorted by pack count, keeping at most limit entries.
+// Solutions with equal pack counts keep their discovery order.
+func insertSolution(list []Solution, sol Solution, limit int) []Solution {
+	i := sort.Search(len(list), func(i int) bool { return list[i].TotalPacks > sol.TotalPacks })
+	if i >= limit {
+		return list
+	}
+	list = append(list, Solution{})
+	copy(list[i+1:], list[i:])
+	list[i] = sol
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list
+}
@@ -0,0 +1,114 @@
+package packsolver_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"github.com/stretchr/testify/assert"
+)
+
+// allMinimalSolutions lists (total, packs) of every distribution in which each pack is
+// needed to cover the quantity, ranked by total and then by pack count.
+func allMinimalSolutions(quantity int, sizes []int) [][2]int {
+	var out [][2]int
+	counts := make([]int, len(sizes))
+	var recurse func(index, total, packs int)
+	recurse = func(index, total, packs int) {
+		if index == len(sizes) {
+			if total < quantity {
+				return
+			}
+			for i, c := range counts {
+				if c > 0 && total-sizes[i] >= quantity {
+					return
+				}
+			}
+			out = append(out, [2]int{total, packs})
+			return
+		}
+		for c := 0; total+c*sizes[index] < quantity+sizes[index]; c++ {
+			counts[index] = c
+			recurse(index+1, total+c*sizes[index], packs+c)
+		}
+		counts[index] = 0
+	}
+	recurse(0, 0, 0)
+
+	sort.Slice(out, func(i, j int) bool {
+		return packsolver.Better(out[i][0], out[i][1], out[j][0], out[j][1])
+	})
+	return out
+}
+
+func TestTopKMatchesBruteForce(t *testing.T) {
+	sizeSets := [][]int{
+		{250, 500, 1000},
+		{3, 5, 7},
+		{4, 6, 9, 20},
+		{12, 20, 30}, // common divisor 2
+	}
+
+	for _, sizes := range sizeSets {
+		for quantity := 1; quantity <= 60; quantity++ {
+			want := allMinimalSolutions(quantity, sizes)
+			k := min(5, len(want))
+
+			got, err := packsolver.SolveTopK(quantity, sizes, 5)
+			assert.NoError(t, err)
+			assert.Len(t, got, k, "quantity %d with %v", quantity, sizes)
+
+			seen := map[string]bool{}
+			for i, sol := range got {
+				assert.Equal(t, want[i][0], sol.TotalItems, "rank %d for %d with %v", i, quantity, sizes)
+				assert.Equal(t, want[i][1], sol.TotalPacks, "rank %d for %d with %v", i, quantity, sizes)
+
+				sum := 0
+				for _, p := range sol.Packs {
+					sum += p.Size * p.Count
+				}
+				assert.Equal(t, sol.TotalItems, sum)
+				assert.Equal(t, sol.TotalPacks, packsolver.TotalPacks(sol.Packs))
+
+				key := fmt.Sprint(sol.Packs)
+				assert.False(t, seen[key], "duplicate solution %s", key)
+				seen[key] = true
+			}
+		}
+	}
+}
+
+func TestTopKFirstMatchesOptimum(t *testing.T) {
+	sizes := []int{23, 31, 53}
+	packs, total, err := packsolver.SolvePackDistribution(5000, sizes)
+	assert.NoError(t, err)
+
+	got, err := packsolver.SolveTopK(5000, sizes, 3)
+	assert.NoError(t, err)
+	assert.Len(t, got, 3)
+	assert.Equal(t, total, got[0].TotalItems)
+	assert.Equal(t, packsolver.TotalPacks(packs), got[0].TotalPacks)
+}
+
+func TestTopKLargeQuantities(t *testing.T) {
+	// the table only holds multiples of the common divisor of the sizes
+	sizes := []int{250, 500, 1000, 2000, 5000}
+	packs, total, err := packsolver.SolvePackDistribution(10_000_001, sizes)
+	assert.NoError(t, err)
+	got, err := packsolver.SolveTopK(10_000_001, sizes, 3)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 3) {
+		assert.Equal(t, total, got[0].TotalItems)
+		assert.Equal(t, packsolver.TotalPacks(packs), got[0].TotalPacks)
+		assert.Equal(t, packs, got[0].Packs)
+	}
+
+	_, err = packsolver.SolveTopK(10_000_001, []int{23, 31, 53}, 3)
+	assert.ErrorIs(t, err, packsolver.ErrQuantityTooLarge)
+}
+
+func TestTopKInvalidK(t *testing.T) {
+	_, err := packsolver.SolveTopK(100, []int{250}, 0)
+	assert.ErrorIs(t, err, packsolver.ErrInvalidK)
+}
@@ -0,0 +1,274 @@
+package packsolver
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ErrInvalidRange is returned for a quantity range that is empty, starts below 1 or is
+// longer than maxAnalysisRange.
+var ErrInvalidRange = errors.New("invalid quantity range")
+
+// maxAnalysisRange caps the number of quantities AnalyzeRange looks at.
+const maxAnalysisRange = 1 << 24
+
+// Analysis describes which quantities a pack configuration can fill exactly and how much
+// the optimal distribution overshoots the others.
+type Analysis struct {
+	Sizes []int `json:"sizes"` // unique, ascending
+	GCD   int   `json:"gcd"`   // every exactly fillable quantity is a multiple of it
+
+	// Largest quantity that cannot be filled exactly (the Frobenius number), 0 when every
+	// quantity can and nil when there is no largest one because the GCD is above 1
+	Frobenius *int `json:"frobenius"`
+	// Largest multiple of the GCD that cannot be filled exactly, 0 when there is none
+	LargestUnfillableMultiple int `json:"largest_unfillable_multiple"`
+	// Number of multiples of the GCD that cannot be filled exactly
+	UnfillableMultiples int `json:"unfillable_multiples"`
+
+	// Largest overage the optimal distribution ships for any quantity, and the smallest
+	// quantity where it happens
+	WorstOverage         int `json:"worst_overage"`
+	WorstOverageQuantity int `json:"worst_overage_quantity"`
+}
+
+// RangeAnalysis describes the overage of the optimal distribution for the quantities
+// From through To.
+type RangeAnalysis struct {
+	From                 int     `json:"from"`
+	To                   int     `json:"to"`
+	ExactQuantities      int     `json:"exact_quantities"` // quantities filled without overage
+	WorstOverage         int     `json:"worst_overage"`
+	WorstOverageQuantity int     `json:"worst_overage_quantity"` // smallest quantity with the worst overage
+	MeanOverage          float64 `json:"mean_overage"`
+}
+
+// Analyze computes the GCD, the Frobenius number and the worst-case overage of the sizes.
+func Analyze(sizes []int) (*Analysis, error) {
+	return AnalyzeContext(context.Background(), sizes)
+}
+
+// AnalyzeContext is Analyze that stops with a *TimeoutError once ctx is done.
+//
+// Sizes are divided by their GCD and the smallest reduced size a is taken as a modulus:
+// for every residue r the smallest fillable total n[r] ≡ r (mod a) is computed with the
+// round-robin algorithm of Böcker and Lipták in O(len(sizes) × a). A total t is then
+// fillable exactly iff t >= n[t mod a], and the largest unfillable one is max(n) - a.
+// The worst overage is found from n as well, in O(a × log a), see residues.worstGap.
+func AnalyzeContext(ctx context.Context, sizes []int) (*Analysis, error) {
+	r, err := newResidues(ctx, sizes)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Analysis{Sizes: r.sizes, GCD: r.gcd}
+	frobenius := r.frobenius()
+	if frobenius > 0 {
+		a.LargestUnfillableMultiple = frobenius * r.gcd
+	}
+	if r.gcd == 1 {
+		a.Frobenius = &a.LargestUnfillableMultiple
+	}
+
+	// Every unfillable reduced total t below n[t mod a] is one of the (n[r] - r) / a
+	// values of its residue class
+	for _, n := range r.smallest {
+		a.UnfillableMultiples += int(n / int64(r.mod))
+	}
+
+	// With t = ceil(q / GCD) the worst quantity of each t is q = GCD·(t-1) + 1, with an
+	// overage of (next(t) - t)·GCD + GCD - 1; above the Frobenius number next(t) = t
+	worstGap, worstT, err := r.worstGap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a.WorstOverage = worstGap*r.gcd + r.gcd - 1
+	a.WorstOverageQuantity = r.gcd*(worstT-1) + 1
+	return a, nil
+}
+
+// AnalyzeRange computes the overage of the optimal distribution for every quantity from
+// through to, which may span at most 16,777,216 quantities.
+func AnalyzeRange(sizes []int, from, to int) (*RangeAnalysis, error) {
+	return AnalyzeRangeContext(context.Background(), sizes, from, to)
+}
+
+// AnalyzeRangeContext is AnalyzeRange that stops with a *TimeoutError once ctx is done.
+func AnalyzeRangeContext(ctx context.Context, sizes []int, from, to int) (*RangeAnalysis, error) {
+	if from < 1 || to < from || to-from >= maxAnalysisRange {
+		return nil, fmt.Errorf("%w: from %d to %d", ErrInvalidRange, from, to)
+	}
+	r, err := newResidues(ctx, sizes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the first fillable reduced total at or above the end of the range, then sweep
+	// down keeping track of the next fillable one; fillable totals are at most mod apart
+	top := (to + r.gcd - 1) / r.gcd
+	next := top
+	for !r.fillable(next) {
+		next++
+	}
+
+	ra := &RangeAnalysis{From: from, To: to}
+	sum := 0.0
+	for q := to; q >= from; q-- {
+		if q%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, &TimeoutError{Err: err}
+			}
+		}
+		t := (q + r.gcd - 1) / r.gcd
+		if r.fillable(t) {
+			next = t
+		}
+		overage := next*r.gcd - q
+		if overage == 0 {
+			ra.ExactQuantities++
+		}
+		if overage >= ra.WorstOverage {
+			ra.WorstOverage, ra.WorstOverageQuantity = overage, q
+		}
+		sum += float64(overage)
+	}
+	ra.MeanOverage = sum / float64(to-from+1)
+	return ra, nil
+}
+
+// residues holds, for the sizes divided by their GCD, the smallest fillable total of every
+// residue class modulo the smallest reduced size.
+type residues struct {
+	sizes    []int
+	gcd      int
+	mod      int
+	smallest []int64 // smallest[r] = smallest fillable reduced total ≡ r (mod mod)
+}
+
+func newResidues(ctx context.Context, sizes []int) (*residues, error) {
+	if err := validate(1, sizes); err != nil { // only the sizes matter here
+		return nil, err
+	}
+
+	r := &residues{sizes: uniqueSizes(sizes)}
+	for _, s := range r.sizes {
+		r.gcd = gcd(r.gcd, s)
+	}
+	r.mod = r.sizes[0] / r.gcd
+	if r.mod > maxTableEntries {
+		return nil, ErrTableTooLarge
+	}
+
+	const inf = math.MaxInt64
+	r.smallest = make([]int64, r.mod)
+	for i := 1; i < r.mod; i++ {
+		r.smallest[i] = inf
+	}
+
+	steps := 0
+	for _, s := range r.sizes[1:] {
+		b := int64(s / r.gcd)
+		d := gcd(r.mod, int(b%int64(r.mod)))
+		for p := 0; p < d; p++ {
+			// Start each cycle of residues from its smallest entry
+			n := int64(inf)
+			for q := p; q < r.mod; q += d {
+				n = min(n, r.smallest[q])
+			}
+			if n == inf {
+				continue
+			}
+			for i := 0; i < r.mod/d; i++ {
+				steps++
+				if steps%cancelCheckInterval == 0 {
+					if err := ctx.Err(); err != nil {
+						return nil, &TimeoutError{Err: err}
+					}
+				}
+				n += b
+				q := int(n % int64(r.mod))
+				n = min(n, r.smallest[q])
+				r.smallest[q] = n
+			}
+		}
+	}
+	return r, nil
+}
+
+// fillable reports whether the reduced total t >= 0 can be filled exactly.
+func (r *residues) fillable(t int) bool {
+	return int64(t) >= r.smallest[t%r.mod]
+}
+
+// worstGap returns the longest run of reduced totals that cannot be filled exactly, as the
+// distance from its first total to the next fillable one, and that first total; the
+// earliest run wins a tie, and (0, 1) is returned when every total can be filled.
+//
+// A run starts after a fillable total p and, as p-mod is fillable too when p >= smallest[p
+// mod mod] + mod, the run after p-mod is at least as long; so the earliest longest run
+// starts after one of the smallest[q]. The next fillable total after p = smallest[q] is
+// either the smallest of the larger smallest[c], or p plus the distance to the next residue
+// whose class is fillable at p. Visiting the smallest[q] in decreasing order, residues only
+// ever stop being fillable, so the next one is found with a union-find over the residues.
+func (r *residues) worstGap(ctx context.Context) (gap, start int, err error) {
+	order := make([]int32, r.mod) // residues by decreasing smallest total, all distinct
+	for q := range order {
+		order[q] = int32(q)
+	}
+	slices.SortFunc(order, func(x, y int32) int { return cmp.Compare(r.smallest[y], r.smallest[x]) })
+
+	// find(q) is the first residue >= q whose class is fillable at the current total, or mod
+	next := make([]int32, r.mod+1)
+	for q := range next {
+		next[q] = int32(q)
+	}
+	find := func(q int32) int32 {
+		for next[q] != q {
+			next[q] = next[next[q]]
+			q = next[q]
+		}
+		return q
+	}
+
+	gap, start = 0, 1
+	for i, q := range order {
+		if i%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, 0, &TimeoutError{Err: err}
+			}
+		}
+		p := r.smallest[q]
+		following := int64(math.MaxInt64)
+		if i > 0 {
+			// the class visited before is the first one fillable only above p
+			prev := order[i-1]
+			following = r.smallest[prev]
+			next[prev] = prev + 1
+		}
+
+		// q itself is still fillable, so the search ends at q + mod at the latest
+		c := int64(find(q + 1))
+		if c == int64(r.mod) {
+			c = int64(find(0)) + int64(r.mod)
+		}
+		following = min(following, p+c-int64(q))
+
+		if g := int(following - p - 1); g >= gap {
+			gap, start = g, int(p)+1
+		}
+	}
+	return gap, start, nil
+}
+
+// frobenius returns the largest reduced total that cannot be filled exactly, or 0.
+func (r *residues) frobenius() int {
+	largest := int64(0)
+	for _, n := range r.smallest {
+		largest = max(largest, n)
+	}
+	return max(0, int(largest)-r.mod)
+}
@@ -0,0 +1,108 @@
+package packsolver_test
+
+import (
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeKnownValues(t *testing.T) {
+	a, err := packsolver.Analyze([]int{20, 9, 6})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{6, 9, 20}, a.Sizes)
+	assert.Equal(t, 1, a.GCD)
+	if assert.NotNil(t, a.Frobenius) {
+		assert.Equal(t, 43, *a.Frobenius) // the McNugget number
+	}
+
+	a, err = packsolver.Analyze([]int{250, 500, 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, 250, a.GCD)
+	assert.Nil(t, a.Frobenius)
+	assert.Equal(t, 0, a.LargestUnfillableMultiple)
+	assert.Equal(t, 0, a.UnfillableMultiples)
+	assert.Equal(t, 249, a.WorstOverage)
+	assert.Equal(t, 1, a.WorstOverageQuantity)
+
+	a, err = packsolver.Analyze([]int{1, 7})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, *a.Frobenius)
+	assert.Equal(t, 0, a.WorstOverage)
+}
+
+func TestAnalyzeMatchesSolver(t *testing.T) {
+	sizeSets := [][]int{
+		{3, 5},
+		{6, 9, 20},
+		{23, 31, 53},
+		{4, 6, 10},
+		{12, 18, 27},
+		{7},
+		{11, 13, 17, 40},
+		{9, 14, 15, 100},
+		{10, 21, 35},
+	}
+
+	for _, sizes := range sizeSets {
+		a, err := packsolver.Analyze(sizes)
+		assert.NoError(t, err)
+
+		// every figure is checked against the DP over a range well past the Frobenius number
+		limit := a.LargestUnfillableMultiple + 3*sizes[len(sizes)-1]
+		largest, unfillable, worst, worstAt := 0, 0, 0, 0
+		for q := 1; q <= limit; q++ {
+			_, total, err := packsolver.SolvePackDistribution(q, sizes)
+			assert.NoError(t, err)
+			if total != q {
+				largest = q
+				if q%a.GCD == 0 {
+					unfillable++
+				}
+			}
+			if total-q > worst {
+				worst, worstAt = total-q, q
+			}
+		}
+
+		if a.GCD == 1 {
+			assert.Equal(t, largest, *a.Frobenius, "%v", sizes)
+		}
+		assert.Equal(t, unfillable, a.UnfillableMultiples, "%v", sizes)
+		assert.Equal(t, worst, a.WorstOverage, "%v", sizes)
+		assert.Equal(t, worstAt, a.WorstOverageQuantity, "%v", sizes)
+
+		r, err := packsolver.AnalyzeRange(sizes, 1, limit)
+		assert.NoError(t, err)
+		assert.Equal(t, worst, r.WorstOverage, "%v", sizes)
+		assert.Equal(t, worstAt, r.WorstOverageQuantity, "%v", sizes)
+	}
+}
+
+func TestAnalyzeLargeCoprimeSizes(t *testing.T) {
+	// the Frobenius numbers are about 1e10 and 1.76e13; with two sizes a < b the longest
+	// run of unfillable quantities is the one below a
+	for _, sizes := range [][]int{{99989, 99991}, {4194301, 4194303}} {
+		a, err := packsolver.Analyze(sizes)
+		assert.NoError(t, err)
+		if assert.NotNil(t, a.Frobenius, "%v", sizes) {
+			assert.Equal(t, sizes[0]*sizes[1]-sizes[0]-sizes[1], *a.Frobenius, "%v", sizes)
+		}
+		assert.Equal(t, sizes[0]-1, a.WorstOverage, "%v", sizes)
+		assert.Equal(t, 1, a.WorstOverageQuantity, "%v", sizes)
+	}
+}
+
+func TestAnalyzeRange(t *testing.T) {
+	r, err := packsolver.AnalyzeRange([]int{250, 500, 1000}, 1, 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, r.ExactQuantities)
+	assert.Equal(t, 249, r.WorstOverage)
+	assert.Equal(t, 1, r.WorstOverageQuantity)
+	assert.InDelta(t, 124.5, r.MeanOverage, 1e-9)
+
+	for _, bounds := range [][2]int{{0, 10}, {10, 9}, {1, 1 << 25}} {
+		_, err := packsolver.AnalyzeRange([]int{250}, bounds[0], bounds[1])
+		assert.ErrorIs(t, err, packsolver.ErrInvalidRange, "%v", bounds)
+	}
+}
@@ -0,0 +1,119 @@
+package packsolver
+
+import (
+	"context"
+	"sort"
+)
+
+// SolveBranchAndBound finds the same optimum as SolvePackDistribution (fewest items, then
+// fewest packs) by a branch-and-bound search whose cost depends on the pack sizes rather
+// than the quantity, so it suits large quantities with a few sizes. Sizes are listed in
+// ascending order.
+func SolveBranchAndBound(quantity int, sizes []int) ([]PackResult, int, error) {
+	return SolveBranchAndBoundContext(context.Background(), quantity, sizes)
+}
+
+// SolveBranchAndBoundContext is SolveBranchAndBound that stops with a *TimeoutError once
+// ctx is done.
+//
+// Sizes are tried largest first and the count of each is branched from the greedy choice
+// downwards, so the first leaf is the greedy answer and later ones only replace it when
+// strictly better. Three facts keep the tree small:
+//   - An optimal distribution never holds s/g or more packs smaller than s, g being the GCD
+//     of s and the smaller sizes: by pigeonhole on prefix sums modulo s some of them add up to
+//     a multiple of s and fewer s-packs could replace them. This limits every count to a
+//     window of about s/g values below the greedy one, independent of the quantity.
+//   - A subtree is pruned when the smallest total it could reach (the remainder rounded up
+//     to a multiple of the GCD of the sizes left) and the fewest packs for it (the remainder
+//     divided by the largest size left, the LP relaxation) cannot beat the incumbent.
+//   - The search stops as soon as the incumbent meets the global lower bound: the quantity
+//     rounded up to a multiple of the GCD of all sizes, in as few packs as the largest size allows.
+func SolveBranchAndBoundContext(ctx context.Context, quantity int, sizes []int) ([]PackResult, int, error) {
+	if err := validate(quantity, sizes); err != nil {
+		return nil, 0, err
+	}
+
+	desc := uniqueSizes(sizes)
+	sort.Sort(sort.Reverse(sort.IntSlice(desc)))
+	n := len(desc)
+
+	// suffixGCD[j] = GCD of desc[j:], the step between totals reachable with those sizes
+	suffixGCD := make([]int, n+1)
+	for j := n - 1; j >= 0; j-- {
+		suffixGCD[j] = gcd(suffixGCD[j+1], desc[j])
+	}
+
+	ceilDiv := func(a, b int) int { return (a + b - 1) / b }
+	bestTotal := ceilDiv(quantity, suffixGCD[0]) * suffixGCD[0]
+	lowerBound := [2]int{bestTotal, ceilDiv(bestTotal, desc[0])}
+
+	best := [2]int{-1, 0} // incumbent (total, packs)
+	bestCounts := make([]int, n)
+	counts := make([]int, n)
+	var ctxErr error
+	done := false
+	nodes := 0
+
+	var recurse func(j, remaining, total, packs int)
+	recurse = func(j, remaining, total, packs int) {
+		if ctxErr != nil || done {
+			return
+		}
+		nodes++
+		if nodes%cancelCheckInterval == 0 {
+			if ctxErr = ctx.Err(); ctxErr != nil {
+				return
+			}
+		}
+
+		if remaining <= 0 || j == n {
+			if remaining <= 0 && (best[0] == -1 || Better(total, packs, best[0], best[1])) {
+				best = [2]int{total, packs}
+				copy(bestCounts, counts)
+				done = best == lowerBound
+			}
+			return
+		}
+
+		// Bound: the smallest reachable total and the fewest packs to reach it
+		step := suffixGCD[j]
+		minTotal := total + ceilDiv(remaining, step)*step
+		minPacks := packs + ceilDiv(minTotal-total, desc[j])
+		if best[0] != -1 && !Better(minTotal, minPacks, best[0], best[1]) {
+			return
+		}
+
+		size := desc[j]
+		hi := ceilDiv(remaining, size)
+		lo := 0
+		if j+1 < n {
+			// packs of the smaller sizes hold at most (size/g - 1) × desc[j+1] items
+			g := gcd(size, suffixGCD[j+1])
+			lo = max(0, ceilDiv(remaining-(size/g-1)*desc[j+1], size))
+		} else {
+			lo = hi // the last size only has to cover what is left
+		}
+
+		for c := hi; c >= lo && ctxErr == nil && !done; c-- {
+			counts[j] = c
+			recurse(j+1, remaining-c*size, total+c*size, packs+c)
+		}
+		counts[j] = 0
+	}
+	recurse(0, quantity, 0, 0)
+
+	if ctxErr != nil {
+		return nil, 0, &TimeoutError{Err: ctxErr}
+	}
+	if best[0] == -1 {
+		return nil, 0, ErrUnreachable
+	}
+
+	var result []PackResult
+	for j := n - 1; j >= 0; j-- {
+		if bestCounts[j] > 0 {
+			result = append(result, PackResult{Size: desc[j], Count: bestCounts[j]})
+		}
+	}
+	return result, best[0], nil
+}
@@ -0,0 +1,74 @@
+package packsolver_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBranchAndBoundMatchesDP(t *testing.T) {
+	sizeSets := [][]int{
+		{250, 500, 1000},
+		{3, 5, 7},
+		{1, 4, 6, 9},
+		{6, 10, 15},
+		{2, 3, 12, 13},
+		{23, 31, 53},
+		{7},
+		{8, 12, 20, 100},
+	}
+
+	for _, sizes := range sizeSets {
+		for quantity := 1; quantity <= 3000; quantity += 13 {
+			wantPacks, wantTotal, err := packsolver.SolvePackDistribution(quantity, sizes)
+			assert.NoError(t, err)
+
+			packs, total, err := packsolver.SolveBranchAndBound(quantity, sizes)
+			assert.NoError(t, err)
+			assert.Equal(t, wantTotal, total, "quantity %d with %v", quantity, sizes)
+			assert.Equal(t, packsolver.TotalPacks(wantPacks), packsolver.TotalPacks(packs), "quantity %d with %v", quantity, sizes)
+
+			sum := 0
+			for _, p := range packs {
+				sum += p.Size * p.Count
+			}
+			assert.Equal(t, total, sum)
+		}
+	}
+}
+
+func TestBranchAndBoundLargeQuantity(t *testing.T) {
+	sizes := []int{23, 31, 53}
+	table, err := packsolver.NewTable(sizes)
+	assert.NoError(t, err)
+
+	// far beyond what the DP can allocate, answered well within the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, quantity := range []int{1_000_000_007, 123_456_789_012} {
+		wantPacks, wantTotal, err := table.Solve(quantity)
+		assert.NoError(t, err)
+
+		packs, total, err := packsolver.SolveBranchAndBoundContext(ctx, quantity, sizes)
+		assert.NoError(t, err)
+		assert.Equal(t, wantTotal, total)
+		assert.Equal(t, packsolver.TotalPacks(wantPacks), packsolver.TotalPacks(packs))
+	}
+}
+
+func BenchmarkBranchAndBoundLargeQuantity(b *testing.B) {
+	sizes := []int{23, 31, 53}
+	for i := 0; i < b.N; i++ {
+		_, _, _ = packsolver.SolveBranchAndBound(10_000_000, sizes)
+	}
+}
+
+func BenchmarkDPLargeQuantity(b *testing.B) {
+	sizes := []int{23, 31, 53}
+	for i := 0; i < b.N; i++ {
+		_, _, _ = packsolver.SolvePackDistribution(10_000_000, sizes)
+	}
+}
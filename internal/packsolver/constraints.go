@@ -0,0 +1,156 @@
+package packsolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidConstraints is returned for constraints that contradict themselves or the sizes.
+var ErrInvalidConstraints = errors.New("invalid constraints")
+
+// Constraints restricts the distributions a solver may return, e.g. "at most 2 packs of
+// 250" or "at least one 1000-pack". Sizes without an entry are not restricted.
+type Constraints struct {
+	Min      map[int]int `json:"min,omitempty"`       // minimum number of packs per size
+	Max      map[int]int `json:"max,omitempty"`       // maximum number of packs per size
+	MaxPacks int         `json:"max_packs,omitempty"` // maximum total number of packs, 0 for no cap
+}
+
+// check validates the constraints against the configured sizes. Maximums for sizes that
+// are not configured are ignored, minimums for them can never be met.
+func (c Constraints) check(sizes []int) error {
+	if c.MaxPacks < 0 {
+		return fmt.Errorf("%w: max_packs must be >= 0", ErrInvalidConstraints)
+	}
+	for size, n := range c.Max {
+		if n < 0 {
+			return fmt.Errorf("%w: max for size %d must be >= 0", ErrInvalidConstraints, size)
+		}
+	}
+
+	configured := map[int]bool{}
+	for _, s := range sizes {
+		configured[s] = true
+	}
+	for size, n := range c.Min {
+		if n < 0 {
+			return fmt.Errorf("%w: min for size %d must be >= 0", ErrInvalidConstraints, size)
+		}
+		if n > 0 && !configured[size] {
+			return fmt.Errorf("%w: size %d is not configured", ErrInvalidConstraints, size)
+		}
+		if hi, ok := c.Max[size]; ok && n > hi {
+			return fmt.Errorf("%w: min %d exceeds max %d for size %d", ErrInvalidConstraints, n, hi, size)
+		}
+	}
+	return nil
+}
+
+// bounds returns the minimum and maximum count (-1 for unlimited) of every size.
+func (c Constraints) bounds(sizes []int) (minCount, maxCount []int) {
+	minCount = make([]int, len(sizes))
+	maxCount = make([]int, len(sizes))
+	for j, size := range sizes {
+		minCount[j] = c.Min[size]
+		maxCount[j] = -1
+		if hi, ok := c.Max[size]; ok {
+			maxCount[j] = hi
+		}
+	}
+	return minCount, maxCount
+}
+
+// SolvePackDistributionConstrained is SolvePackDistribution restricted to distributions
+// satisfying the constraints. Sizes are listed in ascending order.
+func SolvePackDistributionConstrained(quantity int, sizes []int, c Constraints) ([]PackResult, int, error) {
+	return SolvePackDistributionConstrainedContext(context.Background(), quantity, sizes, c)
+}
+
+// SolvePackDistributionConstrainedContext is SolvePackDistributionConstrained that stops
+// with a *TimeoutError once ctx is done.
+//
+// The minimum counts are shipped up front and the rest of the quantity is solved by the
+// bounded DP with the remaining per-size headroom. The DP knows the fewest packs reaching
+// every total, so the pack cap only decides which totals are acceptable.
+func SolvePackDistributionConstrainedContext(ctx context.Context, quantity int, sizes []int, c Constraints) ([]PackResult, int, error) {
+	if err := validate(quantity, sizes); err != nil {
+		return nil, 0, err
+	}
+	if err := c.check(sizes); err != nil {
+		return nil, 0, err
+	}
+
+	sizes = uniqueSizes(sizes)
+	minCount, maxCount := c.bounds(sizes)
+
+	base, basePacks := 0, 0
+	headroom := make([]int, len(sizes))
+	maxSize, available, unlimited := 0, 0, false
+	for j, size := range sizes {
+		maxSize = max(maxSize, size)
+		base += minCount[j] * size
+		basePacks += minCount[j]
+		if maxCount[j] < 0 {
+			headroom[j] = -1
+			unlimited = true
+			continue
+		}
+		headroom[j] = maxCount[j] - minCount[j]
+		available += headroom[j] * size
+	}
+
+	maxPacks := -1
+	if c.MaxPacks > 0 {
+		if maxPacks = c.MaxPacks - basePacks; maxPacks < 0 {
+			return nil, 0, ErrUnreachable
+		}
+	}
+
+	rest := max(quantity-base, 0)
+	limit := rest + maxSize // dropping a pack from a larger total still covers rest
+	if !unlimited {
+		if available < rest {
+			return nil, 0, ErrUnreachable
+		}
+		limit = min(limit, available)
+	}
+
+	counts, total, err := solveBounded(ctx, rest, sizes, headroom, limit, maxPacks)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result []PackResult
+	for j, count := range counts {
+		if count += minCount[j]; count > 0 {
+			result = append(result, PackResult{Size: sizes[j], Count: count})
+		}
+	}
+	return result, base + total, nil
+}
+
+// SolvePackDistribution2Constrained is SolvePackDistribution2 restricted to distributions
+// satisfying the constraints. Sizes are listed in ascending order.
+func SolvePackDistribution2Constrained(quantity int, sizes []int, c Constraints) ([]PackResult, int, error) {
+	return SolvePackDistribution2ConstrainedContext(context.Background(), quantity, sizes, c)
+}
+
+// SolvePackDistribution2ConstrainedContext is SolvePackDistribution2Constrained that stops
+// with a *TimeoutError once ctx is done.
+func SolvePackDistribution2ConstrainedContext(ctx context.Context, quantity int, sizes []int, c Constraints) ([]PackResult, int, error) {
+	if err := validate(quantity, sizes); err != nil {
+		return nil, 0, err
+	}
+	if err := c.check(sizes); err != nil {
+		return nil, 0, err
+	}
+
+	sizes = uniqueSizes(sizes)
+	minCount, maxCount := c.bounds(sizes)
+	maxPacks := -1
+	if c.MaxPacks > 0 {
+		maxPacks = c.MaxPacks
+	}
+	return solveDFS(ctx, quantity, sizes, minCount, maxCount, maxPacks)
+}
@@ -0,0 +1,122 @@
+package packsolver_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"github.com/stretchr/testify/assert"
+)
+
+// constrainedBruteForce returns the best (total, packs) allowed by the constraints,
+// or (-1, 0) when no distribution satisfies them.
+func constrainedBruteForce(quantity int, sizes []int, c packsolver.Constraints) (int, int) {
+	limit := quantity + sizes[len(sizes)-1]
+	for _, s := range sizes {
+		limit += c.Min[s] * s
+	}
+
+	bestTotal, bestPacks := -1, 0
+	var recurse func(index, total, packs int)
+	recurse = func(index, total, packs int) {
+		if index == len(sizes) {
+			if c.MaxPacks > 0 && packs > c.MaxPacks {
+				return
+			}
+			if total >= quantity && (bestTotal == -1 || packsolver.Better(total, packs, bestTotal, bestPacks)) {
+				bestTotal, bestPacks = total, packs
+			}
+			return
+		}
+		size := sizes[index]
+		for count := c.Min[size]; total+count*size <= limit; count++ {
+			if hi, ok := c.Max[size]; ok && count > hi {
+				break
+			}
+			recurse(index+1, total+count*size, packs+count)
+		}
+	}
+	recurse(0, 0, 0)
+	return bestTotal, bestPacks
+}
+
+// checkConstraints asserts that the distribution honours the constraints.
+func checkConstraints(t *testing.T, packs []packsolver.PackResult, c packsolver.Constraints, msg string) {
+	used := map[int]int{}
+	for _, p := range packs {
+		used[p.Size] += p.Count
+	}
+	for size, lo := range c.Min {
+		assert.GreaterOrEqual(t, used[size], lo, msg)
+	}
+	for size, hi := range c.Max {
+		assert.LessOrEqual(t, used[size], hi, msg)
+	}
+	if c.MaxPacks > 0 {
+		assert.LessOrEqual(t, packsolver.TotalPacks(packs), c.MaxPacks, msg)
+	}
+}
+
+func TestConstrainedSolversMatchBruteForce(t *testing.T) {
+	sizes := []int{3, 5, 7, 11}
+	constraints := []packsolver.Constraints{
+		{},
+		{Max: map[int]int{3: 2}},
+		{Min: map[int]int{11: 1}},
+		{Min: map[int]int{3: 1, 7: 1}, Max: map[int]int{5: 0}},
+		{Max: map[int]int{3: 1, 5: 1}, MaxPacks: 4},
+		{MaxPacks: 3},
+		{Min: map[int]int{5: 2}, Max: map[int]int{5: 3, 11: 1}, MaxPacks: 6},
+	}
+
+	for _, c := range constraints {
+		for quantity := 1; quantity <= 60; quantity++ {
+			msg := fmt.Sprintf("%d %+v", quantity, c)
+			wantTotal, wantPacks := constrainedBruteForce(quantity, sizes, c)
+
+			packs, total, err := packsolver.SolvePackDistributionConstrained(quantity, sizes, c)
+			if wantTotal == -1 {
+				assert.ErrorIs(t, err, packsolver.ErrUnreachable, "dp "+msg)
+			} else if assert.NoError(t, err, "dp "+msg) {
+				assert.Equal(t, wantTotal, total, "dp "+msg)
+				assert.Equal(t, wantPacks, packsolver.TotalPacks(packs), "dp "+msg)
+				checkConstraints(t, packs, c, msg)
+			}
+
+			// the DFS only minimizes the total
+			packs, total, err = packsolver.SolvePackDistribution2Constrained(quantity, sizes, c)
+			if wantTotal == -1 {
+				assert.ErrorIs(t, err, packsolver.ErrUnreachable, "dfs "+msg)
+			} else if assert.NoError(t, err, "dfs "+msg) {
+				assert.Equal(t, wantTotal, total, "dfs "+msg)
+				checkConstraints(t, packs, c, msg)
+			}
+		}
+	}
+}
+
+func TestConstrainedMinimumBeyondQuantity(t *testing.T) {
+	// the business rule "always include a 1000-pack" holds even for small orders
+	c := packsolver.Constraints{Min: map[int]int{1000: 1}}
+	packs, total, err := packsolver.SolvePackDistributionConstrained(300, []int{250, 500, 1000}, c)
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, total)
+	assert.Equal(t, []packsolver.PackResult{{Size: 1000, Count: 1}}, packs)
+}
+
+func TestInvalidConstraints(t *testing.T) {
+	sizes := []int{250, 500, 1000}
+	invalid := []packsolver.Constraints{
+		{MaxPacks: -1},
+		{Min: map[int]int{250: -1}},
+		{Max: map[int]int{250: -1}},
+		{Min: map[int]int{300: 1}},
+		{Min: map[int]int{250: 3}, Max: map[int]int{250: 2}},
+	}
+	for _, c := range invalid {
+		_, _, err := packsolver.SolvePackDistributionConstrained(1000, sizes, c)
+		assert.ErrorIs(t, err, packsolver.ErrInvalidConstraints, "%+v", c)
+		_, _, err = packsolver.SolvePackDistribution2Constrained(1000, sizes, c)
+		assert.ErrorIs(t, err, packsolver.ErrInvalidConstraints, "%+v", c)
+	}
+}
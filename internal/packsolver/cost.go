@@ -0,0 +1,107 @@
+package packsolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidCost is returned when a pack size has no cost or a negative one.
+var ErrInvalidCost = errors.New("every pack size needs a cost >= 0")
+
+// costScale converts costs to integer micro-units inside the DP, so that equal costs
+// compare equal and ties fall back to overage instead of floating point noise.
+const costScale = 1_000_000
+
+// PackCost returns the total cost of a distribution. ok is false when a size has no cost.
+func PackCost(packs []PackResult, costs map[int]float64) (cost float64, ok bool) {
+	for _, p := range packs {
+		c, found := costs[p.Size]
+		if !found {
+			return 0, false
+		}
+		cost += c * float64(p.Count)
+	}
+	return cost, true
+}
+
+// SolveMinCost finds the pack distribution covering the quantity with the lowest total cost,
+// where costs maps every pack size to its unit cost (material plus handling).
+// Among equally cheap distributions it picks the smallest total, then the fewest packs.
+// It returns the packs, the total number of items and the cost.
+func SolveMinCost(quantity int, sizes []int, costs map[int]float64) ([]PackResult, int, float64, error) {
+	return SolveMinCostContext(context.Background(), quantity, sizes, costs)
+}
+
+// SolveMinCostContext is SolveMinCost that stops with a *TimeoutError once ctx is done.
+func SolveMinCostContext(ctx context.Context, quantity int, sizes []int, costs map[int]float64) ([]PackResult, int, float64, error) {
+	if err := validate(quantity, sizes); err != nil {
+		return nil, 0, 0, err
+	}
+
+	maxSize := 0
+	unitCost := make([]int64, len(sizes))
+	for j, size := range sizes {
+		c, ok := costs[size]
+		if !ok || c < 0 || math.IsNaN(c) || math.IsInf(c, 0) {
+			return nil, 0, 0, fmt.Errorf("%w: size %d", ErrInvalidCost, size)
+		}
+		unitCost[j] = int64(math.Round(c * costScale))
+		maxSize = max(maxSize, size)
+	}
+
+	// Dropping a pack never makes a solution more expensive, so as in SolvePackDistribution
+	// no optimal total exceeds quantity + maxSize.
+	limit := quantity + maxSize
+	cost := make([]int64, limit+1)  // cost[i] = min cost of packs summing exactly to i
+	packs := make([]int32, limit+1) // packs[i] = fewest packs reaching i at cost[i]
+	last := make([]int32, limit+1)  // last[i] = index of the size added last to reach i
+
+	for i := 1; i <= limit; i++ {
+		cost[i] = math.MaxInt64
+	}
+
+	for i := 1; i <= limit; i++ {
+		if i%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, 0, 0, &TimeoutError{Err: err}
+			}
+		}
+		for j, size := range sizes {
+			if i < size || cost[i-size] == math.MaxInt64 {
+				continue
+			}
+			c, n := cost[i-size]+unitCost[j], packs[i-size]+1
+			if c < cost[i] || (c == cost[i] && n < packs[i]) {
+				cost[i], packs[i], last[i] = c, n, int32(j)
+			}
+		}
+	}
+
+	// Cheapest total >= quantity; scanning upwards keeps the smaller overage on ties
+	bestTotal := -1
+	for i := quantity; i <= limit; i++ {
+		if cost[i] != math.MaxInt64 && (bestTotal == -1 || cost[i] < cost[bestTotal]) {
+			bestTotal = i
+		}
+	}
+	if bestTotal == -1 {
+		return nil, 0, 0, ErrUnreachable
+	}
+
+	counts := make([]int, len(sizes))
+	for i := bestTotal; i > 0; i -= sizes[last[i]] {
+		counts[last[i]]++
+	}
+
+	var result []PackResult
+	for j, count := range counts {
+		if count > 0 {
+			result = append(result, PackResult{Size: sizes[j], Count: count})
+		}
+	}
+
+	totalCost, _ := PackCost(result, costs)
+	return result, bestTotal, totalCost, nil
+}
@@ -0,0 +1,81 @@
+package packsolver_test
+
+import (
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinCostPrefersCheaperPacks(t *testing.T) {
+	sizes := []int{250, 500, 1000}
+	// a 1000-pack costs more than two 500-packs
+	costs := map[int]float64{250: 1.0, 500: 1.5, 1000: 4.0}
+
+	packs, total, cost, err := packsolver.SolveMinCost(2000, sizes, costs)
+	assert.NoError(t, err)
+	assert.Equal(t, 2000, total)
+	assert.InDelta(t, 6.0, cost, 1e-9)
+	assert.Equal(t, []packsolver.PackResult{{Size: 500, Count: 4}}, packs)
+}
+
+func TestMinCostTieFallsBackToOverage(t *testing.T) {
+	sizes := []int{250, 500}
+	// flat price per pack: 400 is cheapest as a single 500-pack
+	costs := map[int]float64{250: 1.0, 500: 1.0}
+
+	packs, total, cost, err := packsolver.SolveMinCost(400, sizes, costs)
+	assert.NoError(t, err)
+	assert.Equal(t, 500, total)
+	assert.InDelta(t, 1.0, cost, 1e-9)
+	assert.Equal(t, []packsolver.PackResult{{Size: 500, Count: 1}}, packs)
+
+	// for 200 one pack of either size costs the same, so the smaller total wins
+	packs, total, _, err = packsolver.SolveMinCost(200, sizes, costs)
+	assert.NoError(t, err)
+	assert.Equal(t, 250, total)
+	assert.Equal(t, []packsolver.PackResult{{Size: 250, Count: 1}}, packs)
+}
+
+func TestMinCostMatchesBruteForce(t *testing.T) {
+	sizes := []int{3, 5, 7}
+	costs := map[int]float64{3: 0.7, 5: 1.1, 7: 1.4}
+
+	for quantity := 1; quantity <= 80; quantity++ {
+		// brute force over all combinations within the search window
+		bestCost, bestTotal := -1.0, 0
+		for a := 0; a*3 <= quantity+7; a++ {
+			for b := 0; a*3+b*5 <= quantity+7; b++ {
+				for c := 0; a*3+b*5+c*7 <= quantity+7; c++ {
+					total := a*3 + b*5 + c*7
+					cost := float64(a)*0.7 + float64(b)*1.1 + float64(c)*1.4
+					if total < quantity {
+						continue
+					}
+					if bestCost < 0 || cost < bestCost-1e-9 || (cost < bestCost+1e-9 && total < bestTotal) {
+						bestCost, bestTotal = cost, total
+					}
+				}
+			}
+		}
+
+		packs, total, cost, err := packsolver.SolveMinCost(quantity, sizes, costs)
+		assert.NoError(t, err)
+		assert.InDelta(t, bestCost, cost, 1e-9, "quantity %d", quantity)
+		assert.Equal(t, bestTotal, total, "quantity %d", quantity)
+
+		packCost, ok := packsolver.PackCost(packs, costs)
+		assert.True(t, ok)
+		assert.InDelta(t, cost, packCost, 1e-9)
+	}
+}
+
+func TestMinCostInvalidCosts(t *testing.T) {
+	sizes := []int{250, 500}
+
+	_, _, _, err := packsolver.SolveMinCost(100, sizes, map[int]float64{250: 1})
+	assert.ErrorIs(t, err, packsolver.ErrInvalidCost)
+
+	_, _, _, err = packsolver.SolveMinCost(100, sizes, map[int]float64{250: 1, 500: -2})
+	assert.ErrorIs(t, err, packsolver.ErrInvalidCost)
+}
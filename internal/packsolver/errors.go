@@ -0,0 +1,48 @@
+package packsolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Errors returned by every solver for input it cannot work with.
+var (
+	ErrNoPackSizes     = errors.New("no pack sizes configured")
+	ErrInvalidQuantity = errors.New("quantity must be > 0")
+	ErrInvalidSize     = errors.New("pack sizes must be > 0")
+	ErrUnreachable     = errors.New("no pack combination covers the quantity")
+)
+
+// validate checks the input shared by all solvers.
+func validate(quantity int, sizes []int) error {
+	if quantity <= 0 {
+		return ErrInvalidQuantity
+	}
+	if len(sizes) == 0 {
+		return ErrNoPackSizes
+	}
+	for _, s := range sizes {
+		if s <= 0 {
+			return fmt.Errorf("%w: got %d", ErrInvalidSize, s)
+		}
+	}
+	return nil
+}
+
+// TimeoutError is returned by the context-aware solvers when the context is cancelled
+// or its deadline passes before a solution is found.
+type TimeoutError struct {
+	Err error // context.DeadlineExceeded or context.Canceled
+}
+
+func (e *TimeoutError) Error() string {
+	if errors.Is(e.Err, context.DeadlineExceeded) {
+		return "solver timed out: " + e.Err.Error()
+	}
+	return "solver cancelled: " + e.Err.Error()
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
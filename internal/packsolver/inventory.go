@@ -0,0 +1,260 @@
+package packsolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ErrInvalidStock is returned when the inventory contains a negative pack count.
+var ErrInvalidStock = errors.New("stock must be >= 0")
+
+// InsufficientStockError is returned when even shipping every pack in stock does not
+// cover the requested quantity.
+type InsufficientStockError struct {
+	Quantity  int          // requested quantity
+	Available int          // items shippable using the whole stock
+	Shortfall int          // Quantity - Available
+	Missing   []PackResult // packs missing from stock to ship the unconstrained optimum
+}
+
+func (e *InsufficientStockError) Error() string {
+	return fmt.Sprintf("insufficient stock: %d items requested, %d available (short by %d)",
+		e.Quantity, e.Available, e.Shortfall)
+}
+
+// SolveWithInventory finds the best pack distribution that uses at most stock[size] packs
+// of each size. Sizes missing from stock are treated as unlimited and stock entries for
+// sizes that are not configured are ignored. The objective is the same as in
+// SolvePackDistribution: fewest items, then fewest packs.
+func SolveWithInventory(quantity int, sizes []int, stock map[int]int) ([]PackResult, int, error) {
+	return SolveWithInventoryContext(context.Background(), quantity, sizes, stock)
+}
+
+// SolveWithInventoryContext is SolveWithInventory that stops with a *TimeoutError once ctx is done.
+func SolveWithInventoryContext(ctx context.Context, quantity int, sizes []int, stock map[int]int) ([]PackResult, int, error) {
+	if err := validate(quantity, sizes); err != nil {
+		return nil, 0, err
+	}
+	for size, count := range stock {
+		if count < 0 {
+			return nil, 0, fmt.Errorf("%w: got %d for size %d", ErrInvalidStock, count, size)
+		}
+	}
+
+	available, unlimited := 0, false
+	for _, size := range uniqueSizes(sizes) {
+		count, ok := stock[size]
+		if !ok {
+			unlimited = true
+			continue
+		}
+		available += count * size
+	}
+
+	if !unlimited && available < quantity {
+		return nil, 0, insufficientStock(ctx, quantity, sizes, stock, available)
+	}
+	return SolvePackDistributionConstrainedContext(ctx, quantity, sizes, Constraints{Max: stock})
+}
+
+// insufficientStock builds the error describing how far the stock is from the
+// distribution the unconstrained solver would ship.
+func insufficientStock(ctx context.Context, quantity int, sizes []int, stock map[int]int, available int) error {
+	ideal, _, err := SolvePackDistributionContext(ctx, quantity, sizes)
+	if err != nil {
+		return err
+	}
+
+	var missing []PackResult
+	for _, p := range ideal {
+		if short := p.Count - stock[p.Size]; short > 0 {
+			missing = append(missing, PackResult{Size: p.Size, Count: short})
+		}
+	}
+
+	return &InsufficientStockError{
+		Quantity:  quantity,
+		Available: available,
+		Shortfall: quantity - available,
+		Missing:   missing,
+	}
+}
+
+// uniqueSizes returns the sizes sorted ascending without duplicates.
+func uniqueSizes(sizes []int) []int {
+	out := append([]int(nil), sizes...)
+	sort.Ints(out)
+	n := 0
+	for i, s := range out {
+		if i == 0 || s != out[n-1] {
+			out[n] = s
+			n++
+		}
+	}
+	return out[:n]
+}
+
+// maxChoiceEntries caps the per-size choices solveBounded keeps to rebuild its answer
+// (4 bytes each). Above it the answer is rebuilt by recomputing the DP layers instead,
+// which costs more time but keeps the memory at two layers, like the unconstrained DP.
+const maxChoiceEntries = 1 << 22
+
+// solveBounded runs a bounded-knapsack DP over totals 0..limit in which sizes[j] may be
+// used at most maxCount[j] times (-1 for unlimited). It returns the per-size counts of the
+// smallest total >= quantity that can be reached with at most maxPacks packs (-1 for no
+// cap), using the fewest packs among the combinations reaching it.
+//
+// Sizes are processed one at a time, see boundedDP.layer. While len(sizes) × limit stays
+// within maxChoiceEntries, the count chosen for each (size, total) is kept to rebuild the
+// answer. Beyond it, the layers before each size are recomputed while walking back, which
+// takes O(len(sizes)² × limit) time but only O(limit) memory.
+func solveBounded(ctx context.Context, quantity int, sizes []int, maxCount []int, limit, maxPacks int) ([]int, int, error) {
+	dp := &boundedDP{
+		ctx:      ctx,
+		sizes:    sizes,
+		maxCount: maxCount,
+		prev:     make([]int32, limit+1),
+		cur:      make([]int32, limit+1),
+	}
+	var choice [][]int32 // choice[j][t] = packs of sizes[j] used to reach t
+	keep := int64(len(sizes))*int64(limit+1) <= maxChoiceEntries
+	if keep {
+		choice = make([][]int32, len(sizes))
+	}
+
+	packs, err := dp.run(len(sizes), choice)
+	if err != nil {
+		return nil, 0, err
+	}
+	bestTotal := -1
+	for t := quantity; t <= limit; t++ {
+		if packs[t] != boundedInf && (maxPacks < 0 || int(packs[t]) <= maxPacks) {
+			bestTotal = t
+			break
+		}
+	}
+	if bestTotal == -1 {
+		return nil, 0, ErrUnreachable
+	}
+
+	counts := make([]int, len(sizes))
+	need := packs[bestTotal] // fewest packs reaching t with sizes[:j+1]
+	for j, t := len(sizes)-1, bestTotal; j >= 0; j-- {
+		if keep {
+			counts[j] = int(choice[j][t])
+		} else {
+			before, err := dp.run(j, nil)
+			if err != nil {
+				return nil, 0, err
+			}
+			c, ok := dp.count(before, j, t, need)
+			if !ok {
+				return nil, 0, ErrUnreachable // cannot happen: the layers are recomputed identically
+			}
+			counts[j] = c
+			need -= int32(c)
+		}
+		t -= counts[j] * sizes[j]
+	}
+	return counts, bestTotal, nil
+}
+
+// boundedInf marks a total that the sizes processed so far cannot reach.
+const boundedInf = math.MaxInt32
+
+// boundedDP holds the two layers of the bounded-knapsack DP and the buffers reused by
+// every pass over them.
+type boundedDP struct {
+	ctx       context.Context
+	sizes     []int
+	maxCount  []int
+	prev, cur []int32 // packs[t] = min packs reaching t with the sizes processed so far
+	deque     []int32 // chain positions m with increasing prev[m] - m
+	steps     int
+}
+
+// run computes the fewest packs reaching every total with sizes[:layers] and returns that
+// layer; it is overwritten by the next run. When choice is not nil, the count chosen for
+// each size and total is stored in it.
+func (d *boundedDP) run(layers int, choice [][]int32) ([]int32, error) {
+	prev, cur := d.prev, d.cur
+	prev[0] = 0
+	for t := 1; t < len(prev); t++ {
+		prev[t] = boundedInf
+	}
+	for j := 0; j < layers; j++ {
+		var chosen []int32
+		if choice != nil {
+			chosen = make([]int32, len(prev))
+			choice[j] = chosen
+		}
+		if err := d.layer(prev, cur, d.sizes[j], d.maxCount[j], chosen); err != nil {
+			return nil, err
+		}
+		prev, cur = cur, prev
+	}
+	return prev, nil
+}
+
+// layer adds up to hi packs (-1 for unlimited) of size to every total of prev and stores
+// the fewest packs in cur. For a size s, totals sharing the same residue modulo s form a
+// chain, and choosing c packs of s at position k of the chain means taking prev at
+// position k-c; a monotone deque keeps the best such position in a sliding window of width
+// hi, so every layer costs O(limit). Among equally good counts the smallest one is chosen.
+func (d *boundedDP) layer(prev, cur []int32, size, hi int, chosen []int32) error {
+	limit := len(prev) - 1
+	for r := 0; r < size && r <= limit; r++ {
+		deque := d.deque[:0]
+		head := 0
+		key := func(m int32) int64 { return int64(prev[r+int(m)*size]) - int64(m) }
+
+		for k := int32(0); r+int(k)*size <= limit; k++ {
+			d.steps++
+			if d.steps%cancelCheckInterval == 0 {
+				if err := d.ctx.Err(); err != nil {
+					return &TimeoutError{Err: err}
+				}
+			}
+
+			t := r + int(k)*size
+			if prev[t] != boundedInf {
+				for len(deque) > head && key(deque[len(deque)-1]) >= key(k) {
+					deque = deque[:len(deque)-1]
+				}
+				deque = append(deque, k)
+			}
+			if hi >= 0 {
+				for len(deque) > head && int(deque[head]) < int(k)-hi {
+					head++
+				}
+			}
+
+			if len(deque) == head {
+				cur[t] = boundedInf
+				continue
+			}
+			m := deque[head]
+			cur[t] = prev[r+int(m)*size] + (k - m)
+			if chosen != nil {
+				chosen[t] = k - m
+			}
+		}
+		d.deque = deque
+	}
+	return nil
+}
+
+// count returns the smallest count of sizes[j] that reaches t with need packs on top of
+// before, the layer of sizes[:j]; it is the count layer would have chosen.
+func (d *boundedDP) count(before []int32, j, t int, need int32) (int, bool) {
+	size, hi := d.sizes[j], d.maxCount[j]
+	for c := 0; t-c*size >= 0 && (hi < 0 || c <= hi); c++ {
+		if p := before[t-c*size]; p != boundedInf && p+int32(c) == need {
+			return c, true
+		}
+	}
+	return 0, false
+}
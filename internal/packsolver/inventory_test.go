@@ -0,0 +1,127 @@
+package packsolver_test
+
+import (
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"github.com/stretchr/testify/assert"
+)
+
+// bruteForceStock returns the best total and pack count using at most stock[size]
+// packs of each size, or -1 if the stock cannot cover the quantity.
+func bruteForceStock(quantity int, sizes []int, stock map[int]int) (int, int) {
+	bestTotal, bestPacks := -1, 0
+	var recurse func(index, total, packs int)
+	recurse = func(index, total, packs int) {
+		if index == len(sizes) {
+			if total >= quantity && (bestTotal == -1 || packsolver.Better(total, packs, bestTotal, bestPacks)) {
+				bestTotal, bestPacks = total, packs
+			}
+			return
+		}
+		for count := 0; count <= stock[sizes[index]]; count++ {
+			recurse(index+1, total+count*sizes[index], packs+count)
+		}
+	}
+	recurse(0, 0, 0)
+	return bestTotal, bestPacks
+}
+
+func TestInventoryRespectsStock(t *testing.T) {
+	sizes := []int{250, 500, 1000}
+	stock := map[int]int{250: 10, 500: 10, 1000: 1}
+
+	packs, total, err := packsolver.SolveWithInventory(3000, sizes, stock)
+	assert.NoError(t, err)
+	assert.Equal(t, 3000, total)
+	// only one 1000-pack is available, the rest has to come from 500-packs
+	assert.Equal(t, []packsolver.PackResult{{Size: 500, Count: 4}, {Size: 1000, Count: 1}}, packs)
+}
+
+func TestInventoryMatchesBruteForce(t *testing.T) {
+	sizes := []int{3, 5, 7, 11}
+	stocks := []map[int]int{
+		{3: 2, 5: 1, 7: 3, 11: 1},
+		{3: 0, 5: 4, 7: 0, 11: 2},
+		{3: 5, 5: 5, 7: 5, 11: 5},
+	}
+
+	for _, stock := range stocks {
+		for quantity := 1; quantity <= 60; quantity++ {
+			wantTotal, wantPacks := bruteForceStock(quantity, sizes, stock)
+
+			packs, total, err := packsolver.SolveWithInventory(quantity, sizes, stock)
+			if wantTotal == -1 {
+				var stockErr *packsolver.InsufficientStockError
+				assert.ErrorAs(t, err, &stockErr, "quantity %d with %v", quantity, stock)
+				continue
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, wantTotal, total, "quantity %d with %v", quantity, stock)
+			assert.Equal(t, wantPacks, packsolver.TotalPacks(packs), "quantity %d with %v", quantity, stock)
+			for _, p := range packs {
+				assert.LessOrEqual(t, p.Count, stock[p.Size])
+			}
+		}
+	}
+}
+
+func TestInventoryLargeQuantities(t *testing.T) {
+	// Scaling sizes and quantity by the same factor scales the answer, so brute force on
+	// the small problem checks orders large enough to rebuild the answer without keeping
+	// every choice.
+	const factor = 40000
+	sizes := []int{3, 5, 7, 11}
+	stock := map[int]int{3: 5, 5: 5, 7: 5, 11: 5}
+	scaledSizes := make([]int, len(sizes))
+	scaledStock := map[int]int{}
+	for i, size := range sizes {
+		scaledSizes[i] = size * factor
+		scaledStock[size*factor] = stock[size]
+	}
+
+	for _, quantity := range []int{58, 120} {
+		wantTotal, wantPacks := bruteForceStock(quantity, sizes, stock)
+		packs, total, err := packsolver.SolveWithInventory(quantity*factor, scaledSizes, scaledStock)
+		assert.NoError(t, err)
+		assert.Equal(t, wantTotal*factor, total, "quantity %d", quantity)
+		assert.Equal(t, wantPacks, packsolver.TotalPacks(packs), "quantity %d", quantity)
+		for _, p := range packs {
+			assert.LessOrEqual(t, p.Count, scaledStock[p.Size])
+		}
+	}
+
+	// one stock entry and unlimited other sizes
+	packs, total, err := packsolver.SolveWithInventory(1_000_000, []int{250, 500, 1000, 2000, 5000}, map[int]int{5000: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 1_000_000, total)
+	assert.Equal(t, []packsolver.PackResult{{Size: 2000, Count: 475}, {Size: 5000, Count: 10}}, packs)
+}
+
+func TestInventoryUnlistedSizesAreUnlimited(t *testing.T) {
+	sizes := []int{250, 500, 1000}
+	unlimited, uTotal, err := packsolver.SolvePackDistribution(12001, sizes)
+	assert.NoError(t, err)
+
+	packs, total, err := packsolver.SolveWithInventory(12001, sizes, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uTotal, total)
+	assert.Equal(t, packsolver.TotalPacks(unlimited), packsolver.TotalPacks(packs))
+}
+
+func TestInventoryInsufficientStock(t *testing.T) {
+	sizes := []int{250, 500, 1000}
+	stock := map[int]int{250: 1, 500: 1, 1000: 2}
+
+	_, _, err := packsolver.SolveWithInventory(5000, sizes, stock)
+	var stockErr *packsolver.InsufficientStockError
+	assert.ErrorAs(t, err, &stockErr)
+	assert.Equal(t, 2750, stockErr.Available)
+	assert.Equal(t, 2250, stockErr.Shortfall)
+	assert.Equal(t, []packsolver.PackResult{{Size: 1000, Count: 3}}, stockErr.Missing)
+}
+
+func TestInventoryInvalidStock(t *testing.T) {
+	_, _, err := packsolver.SolveWithInventory(100, []int{250}, map[int]int{250: -1})
+	assert.ErrorIs(t, err, packsolver.ErrInvalidStock)
+}
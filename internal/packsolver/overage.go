@@ -0,0 +1,66 @@
+package packsolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidOverage is returned when a negative overage tolerance is requested.
+var ErrInvalidOverage = errors.New("max overage must be >= 0")
+
+// OverageError is returned when the best distribution ships more than the tolerated overage.
+type OverageError struct {
+	Quantity   int // requested quantity
+	MaxOverage int // tolerated overage, 0 for exact-only
+	Total      int // smallest total that can actually be shipped
+}
+
+func (e *OverageError) Error() string {
+	if e.MaxOverage == 0 {
+		return fmt.Sprintf("no exact distribution for %d items: the closest total is %d (overage %d)",
+			e.Quantity, e.Total, e.Total-e.Quantity)
+	}
+	return fmt.Sprintf("no distribution for %d items within an overage of %d: the closest total is %d (overage %d)",
+		e.Quantity, e.MaxOverage, e.Total, e.Total-e.Quantity)
+}
+
+// CheckOverage returns an *OverageError when total exceeds quantity by more than maxOverage.
+// It is only conclusive for solvers that minimize the total, such as the DP.
+func CheckOverage(quantity, total, maxOverage int) error {
+	if maxOverage < 0 {
+		return ErrInvalidOverage
+	}
+	if total-quantity > maxOverage {
+		return &OverageError{Quantity: quantity, MaxOverage: maxOverage, Total: total}
+	}
+	return nil
+}
+
+// SolveWithinOverage is SolvePackDistribution that fails with an *OverageError instead of
+// shipping more than quantity+maxOverage items. The DP already inspects every total in its
+// quantity+maxSize search window, so its first reachable total is the smallest possible one
+// and nothing fits the tolerance when that one does not.
+func SolveWithinOverage(quantity int, sizes []int, maxOverage int) ([]PackResult, int, error) {
+	return SolveWithinOverageContext(context.Background(), quantity, sizes, maxOverage)
+}
+
+// SolveWithinOverageContext is SolveWithinOverage that stops with a *TimeoutError once ctx is done.
+func SolveWithinOverageContext(ctx context.Context, quantity int, sizes []int, maxOverage int) ([]PackResult, int, error) {
+	if maxOverage < 0 {
+		return nil, 0, ErrInvalidOverage
+	}
+	packs, total, err := SolvePackDistributionContext(ctx, quantity, sizes)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := CheckOverage(quantity, total, maxOverage); err != nil {
+		return nil, 0, err
+	}
+	return packs, total, nil
+}
+
+// SolveExact returns a distribution summing exactly to the quantity, or an *OverageError.
+func SolveExact(quantity int, sizes []int) ([]PackResult, int, error) {
+	return SolveWithinOverage(quantity, sizes, 0)
+}
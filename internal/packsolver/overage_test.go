@@ -0,0 +1,45 @@
+package packsolver_test
+
+import (
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolveExact(t *testing.T) {
+	sizes := []int{250, 500, 1000}
+
+	packs, total, err := packsolver.SolveExact(1750, sizes)
+	assert.NoError(t, err)
+	assert.Equal(t, 1750, total)
+	assert.Equal(t, 3, packsolver.TotalPacks(packs))
+
+	_, _, err = packsolver.SolveExact(1700, sizes)
+	var overageErr *packsolver.OverageError
+	assert.ErrorAs(t, err, &overageErr)
+	assert.Equal(t, 1750, overageErr.Total)
+	assert.Equal(t, 0, overageErr.MaxOverage)
+	assert.Contains(t, err.Error(), "closest total is 1750")
+}
+
+func TestSolveWithinOverage(t *testing.T) {
+	sizes := []int{250, 500, 1000}
+
+	_, total, err := packsolver.SolveWithinOverage(1700, sizes, 50)
+	assert.NoError(t, err)
+	assert.Equal(t, 1750, total)
+
+	_, _, err = packsolver.SolveWithinOverage(1700, sizes, 49)
+	var overageErr *packsolver.OverageError
+	assert.ErrorAs(t, err, &overageErr)
+
+	_, _, err = packsolver.SolveWithinOverage(1700, sizes, -1)
+	assert.ErrorIs(t, err, packsolver.ErrInvalidOverage)
+}
+
+func TestCheckOverage(t *testing.T) {
+	assert.NoError(t, packsolver.CheckOverage(100, 100, 0))
+	assert.NoError(t, packsolver.CheckOverage(100, 110, 10))
+	assert.Error(t, packsolver.CheckOverage(100, 111, 10))
+}
@@ -0,0 +1,147 @@
+package packsolver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrInvalidPackaging is returned for a packaging hierarchy that cannot hold the packs.
+var ErrInvalidPackaging = errors.New("invalid packaging hierarchy")
+
+// PackLevel is the level name of the packs themselves in a packaging tree.
+const PackLevel = "pack"
+
+// PackagingLevel is one level of shipping units above the packs, e.g. a case or a pallet.
+// The first level holds packs of a single size, PerPack of them per unit; every further
+// level holds Capacity units of the level below, in any mix.
+type PackagingLevel struct {
+	Name     string      `json:"name"`
+	PerPack  map[int]int `json:"per_pack,omitempty"` // first level only: packs per unit, by pack size
+	Capacity int         `json:"capacity,omitempty"` // further levels: units of the level below per unit
+}
+
+// PackageNode is a group of identical units in a packaging tree.
+type PackageNode struct {
+	Level    string        `json:"level"`              // name of the packaging level, or "pack"
+	Size     int           `json:"size,omitempty"`     // items per pack, pack level only
+	Count    int           `json:"count"`              // number of identical units in the group
+	Items    int           `json:"items"`              // items held by one unit
+	Contents []PackageNode `json:"contents,omitempty"` // what one unit holds
+}
+
+// ValidatePackaging checks that the levels form a hierarchy that can hold packs of every size.
+func ValidatePackaging(levels []PackagingLevel, sizes []int) error {
+	if len(levels) == 0 {
+		return fmt.Errorf("%w: no levels", ErrInvalidPackaging)
+	}
+
+	names := map[string]bool{PackLevel: true}
+	for i, level := range levels {
+		if level.Name == "" || names[level.Name] {
+			return fmt.Errorf("%w: level names must be unique, non-empty and not %q", ErrInvalidPackaging, PackLevel)
+		}
+		names[level.Name] = true
+
+		if i == 0 {
+			if level.Capacity != 0 {
+				return fmt.Errorf("%w: %s holds packs and needs per_pack instead of capacity", ErrInvalidPackaging, level.Name)
+			}
+			continue
+		}
+		if len(level.PerPack) > 0 {
+			return fmt.Errorf("%w: only the first level may set per_pack", ErrInvalidPackaging)
+		}
+		if level.Capacity <= 0 {
+			return fmt.Errorf("%w: %s needs a capacity > 0", ErrInvalidPackaging, level.Name)
+		}
+	}
+
+	first := levels[0]
+	for size, n := range first.PerPack {
+		if n <= 0 {
+			return fmt.Errorf("%w: %s must hold > 0 packs of %d", ErrInvalidPackaging, first.Name, size)
+		}
+	}
+	for _, size := range sizes {
+		if _, ok := first.PerPack[size]; !ok {
+			return fmt.Errorf("%w: %s has no per_pack entry for size %d", ErrInvalidPackaging, first.Name, size)
+		}
+	}
+	return nil
+}
+
+// RollUp packs a distribution into the packaging levels and returns the groups of
+// top-level units. Large packs are packed first and every level is filled in order, so
+// only the last unit of each run is partially filled.
+func RollUp(packs []PackResult, levels []PackagingLevel) ([]PackageNode, error) {
+	sizes := make([]int, 0, len(packs))
+	for _, p := range packs {
+		sizes = append(sizes, p.Size)
+	}
+	if err := ValidatePackaging(levels, sizes); err != nil {
+		return nil, err
+	}
+
+	ordered := append([]PackResult(nil), packs...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Size > ordered[j].Size })
+
+	// First level: units of packs of one size
+	first := levels[0]
+	var nodes []PackageNode
+	for _, p := range ordered {
+		perUnit := first.PerPack[p.Size]
+		pack := PackageNode{Level: PackLevel, Size: p.Size, Items: p.Size}
+		if full := p.Count / perUnit; full > 0 {
+			pack.Count = perUnit
+			nodes = append(nodes, PackageNode{Level: first.Name, Count: full, Items: perUnit * p.Size, Contents: []PackageNode{pack}})
+		}
+		if rest := p.Count % perUnit; rest > 0 {
+			pack.Count = rest
+			nodes = append(nodes, PackageNode{Level: first.Name, Count: 1, Items: rest * p.Size, Contents: []PackageNode{pack}})
+		}
+	}
+
+	for _, level := range levels[1:] {
+		nodes = fill(nodes, level)
+	}
+	return nodes, nil
+}
+
+// fill puts the groups of lower units, in order, into units of the given level. Runs of
+// completely filled units holding a single group are merged into one node.
+func fill(lower []PackageNode, level PackagingLevel) []PackageNode {
+	var out, open []PackageNode
+	used, items := 0, 0
+	flush := func() {
+		if used > 0 {
+			out = append(out, PackageNode{Level: level.Name, Count: 1, Items: items, Contents: open})
+			open, used, items = nil, 0, 0
+		}
+	}
+
+	for _, group := range lower {
+		left := group.Count
+		for left > 0 {
+			unit := group
+			if used == 0 && left >= level.Capacity {
+				full := left / level.Capacity
+				unit.Count = level.Capacity
+				out = append(out, PackageNode{Level: level.Name, Count: full, Items: level.Capacity * group.Items, Contents: []PackageNode{unit}})
+				left -= full * level.Capacity
+				continue
+			}
+
+			unit.Count = min(left, level.Capacity-used)
+			open = append(open, unit)
+			used += unit.Count
+			items += unit.Count * group.Items
+			left -= unit.Count
+			if used == level.Capacity {
+				flush()
+			}
+		}
+	}
+	flush()
+	return out
+}
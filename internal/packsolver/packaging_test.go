@@ -0,0 +1,98 @@
+package packsolver_test
+
+import (
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"github.com/stretchr/testify/assert"
+)
+
+var casesAndPallets = []packsolver.PackagingLevel{
+	{Name: "case", PerPack: map[int]int{250: 24, 500: 12, 1000: 6}},
+	{Name: "pallet", Capacity: 40},
+}
+
+// countItems returns the number of items held by the nodes and the number of packs at the leaves.
+func countItems(t *testing.T, nodes []packsolver.PackageNode) (items, packs int) {
+	for _, n := range nodes {
+		if n.Level == packsolver.PackLevel {
+			items += n.Count * n.Size
+			packs += n.Count
+			continue
+		}
+		i, p := countItems(t, n.Contents)
+		assert.Equal(t, n.Items, i, "items of one %s", n.Level)
+		items += n.Count * i
+		packs += n.Count * p
+	}
+	return items, packs
+}
+
+func TestRollUp(t *testing.T) {
+	packs := []packsolver.PackResult{{Size: 250, Count: 1}, {Size: 1000, Count: 500}}
+
+	nodes, err := packsolver.RollUp(packs, casesAndPallets)
+	assert.NoError(t, err)
+
+	// 500 packs of 1000 make 83 full cases and one with 2 packs; with the case of
+	// a single 250-pack that is 85 cases on 2 full pallets and one holding 5 cases
+	want := []packsolver.PackageNode{
+		{Level: "pallet", Count: 2, Items: 240000, Contents: []packsolver.PackageNode{
+			{Level: "case", Count: 40, Items: 6000, Contents: []packsolver.PackageNode{
+				{Level: "pack", Size: 1000, Count: 6, Items: 1000},
+			}},
+		}},
+		{Level: "pallet", Count: 1, Items: 20250, Contents: []packsolver.PackageNode{
+			{Level: "case", Count: 3, Items: 6000, Contents: []packsolver.PackageNode{
+				{Level: "pack", Size: 1000, Count: 6, Items: 1000},
+			}},
+			{Level: "case", Count: 1, Items: 2000, Contents: []packsolver.PackageNode{
+				{Level: "pack", Size: 1000, Count: 2, Items: 1000},
+			}},
+			{Level: "case", Count: 1, Items: 250, Contents: []packsolver.PackageNode{
+				{Level: "pack", Size: 250, Count: 1, Items: 250},
+			}},
+		}},
+	}
+	assert.Equal(t, want, nodes)
+}
+
+func TestRollUpKeepsEveryPack(t *testing.T) {
+	levels := []packsolver.PackagingLevel{
+		{Name: "case", PerPack: map[int]int{23: 5, 31: 4, 53: 3}},
+		{Name: "layer", Capacity: 7},
+		{Name: "pallet", Capacity: 3},
+	}
+
+	for quantity := 1; quantity <= 5000; quantity += 97 {
+		packs, total, err := packsolver.SolvePackDistribution(quantity, []int{23, 31, 53})
+		assert.NoError(t, err)
+
+		nodes, err := packsolver.RollUp(packs, levels)
+		assert.NoError(t, err)
+		items, count := countItems(t, nodes)
+		assert.Equal(t, total, items, "quantity %d", quantity)
+		assert.Equal(t, packsolver.TotalPacks(packs), count, "quantity %d", quantity)
+		for _, n := range nodes {
+			assert.Equal(t, "pallet", n.Level)
+		}
+	}
+}
+
+func TestValidatePackaging(t *testing.T) {
+	sizes := []int{250, 500, 1000}
+	assert.NoError(t, packsolver.ValidatePackaging(casesAndPallets, sizes))
+
+	invalid := [][]packsolver.PackagingLevel{
+		nil,
+		{{Name: "case", PerPack: map[int]int{250: 24, 500: 12}}},
+		{{Name: "case", PerPack: map[int]int{250: 24, 500: 12, 1000: 0}}},
+		{{Name: "case", PerPack: map[int]int{250: 24, 500: 12, 1000: 6}, Capacity: 4}},
+		{{Name: "case", PerPack: map[int]int{250: 24, 500: 12, 1000: 6}}, {Name: "pallet"}},
+		{{Name: "case", PerPack: map[int]int{250: 24, 500: 12, 1000: 6}}, {Name: "case", Capacity: 40}},
+		{{Name: "pack", PerPack: map[int]int{250: 24, 500: 12, 1000: 6}}},
+	}
+	for _, levels := range invalid {
+		assert.ErrorIs(t, packsolver.ValidatePackaging(levels, sizes), packsolver.ErrInvalidPackaging, "%+v", levels)
+	}
+}
@@ -0,0 +1,198 @@
+package packsolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+// Errors returned by the pack size optimizer for options it cannot work with.
+var (
+	ErrInvalidCount   = errors.New("number of pack sizes must be > 0")
+	ErrInvalidPenalty = errors.New("size penalty must be >= 0")
+)
+
+// maxCandidates caps the candidate sizes the optimizer chooses from.
+const maxCandidates = 100
+
+// maxSwapRounds caps the improvement rounds after the greedy selection.
+const maxSwapRounds = 20
+
+// RecommendOptions configures the pack size optimizer.
+type RecommendOptions struct {
+	Count       int     // number of sizes to recommend; with SizePenalty > 0 it is an upper bound
+	SizePenalty float64 // cost per distinct size, in items of overage; 0 always returns Count sizes
+	Candidates  []int   // sizes to choose from; defaults to the most frequent quantities
+	Solver      Solver  // evaluates candidate sets; defaults to the branch-and-bound solver
+}
+
+// Recommendation is a set of pack sizes together with how it performs on the sample.
+type Recommendation struct {
+	Sizes        []int   `json:"sizes"`
+	TotalOverage int     `json:"total_overage"` // items shipped beyond the quantities, summed over the sample
+	MeanOverage  float64 `json:"mean_overage"`
+	ExactOrders  int     `json:"exact_orders"` // orders of the sample filled without overage
+	Score        float64 `json:"score"`        // TotalOverage + SizePenalty × len(Sizes), the value minimized
+}
+
+// Recommend suggests the pack sizes that minimize the total overage (plus the size penalty)
+// over a sample of past order quantities.
+func Recommend(quantities []int, opts RecommendOptions) (*Recommendation, error) {
+	return RecommendContext(context.Background(), quantities, opts)
+}
+
+// RecommendContext is Recommend that stops with a *TimeoutError once ctx is done.
+//
+// Choosing the best sizes is a p-median-like problem, so the optimizer is a local search:
+// sizes are added greedily, each time the candidate that lowers the score most, and the
+// set is then improved by swapping a chosen size for a candidate until no swap helps.
+// Every set is scored by running the solver on each distinct quantity of the sample,
+// weighted by how often it occurs.
+func RecommendContext(ctx context.Context, quantities []int, opts RecommendOptions) (*Recommendation, error) {
+	if len(quantities) == 0 {
+		return nil, fmt.Errorf("%w: no quantities", ErrInvalidQuantity)
+	}
+	if opts.Count <= 0 {
+		return nil, ErrInvalidCount
+	}
+	if opts.SizePenalty < 0 || math.IsNaN(opts.SizePenalty) {
+		return nil, ErrInvalidPenalty
+	}
+
+	weights := map[int]int{}
+	for _, q := range quantities {
+		if q <= 0 {
+			return nil, ErrInvalidQuantity
+		}
+		weights[q]++
+	}
+
+	candidates, err := recommendCandidates(weights, opts.Candidates)
+	if err != nil {
+		return nil, err
+	}
+	solver := opts.Solver
+	if solver == nil {
+		solver = SolverFunc(SolveBranchAndBoundContext)
+	}
+
+	e := &evaluator{ctx: ctx, solver: solver, weights: weights, penalty: opts.SizePenalty, seen: map[string]*Recommendation{}}
+
+	// Greedy selection; with a penalty a size is only added while it lowers the score
+	var chosen []int
+	var best *Recommendation
+	for len(chosen) < min(opts.Count, len(candidates)) {
+		var next *Recommendation
+		for _, c := range candidates {
+			if slices.Contains(chosen, c) {
+				continue
+			}
+			r, err := e.score(append(slices.Clone(chosen), c))
+			if err != nil {
+				return nil, err
+			}
+			if next == nil || r.Score < next.Score {
+				next = r
+			}
+		}
+		if best != nil && opts.SizePenalty > 0 && next.Score >= best.Score {
+			break
+		}
+		best, chosen = next, next.Sizes
+	}
+
+	// Swap improvement
+	for round := 0; round < maxSwapRounds; round++ {
+		improved := false
+		for i := range chosen {
+			for _, c := range candidates {
+				if slices.Contains(chosen, c) {
+					continue
+				}
+				trial := slices.Clone(chosen)
+				trial[i] = c
+				r, err := e.score(trial)
+				if err != nil {
+					return nil, err
+				}
+				if r.Score < best.Score {
+					best, improved = r, true
+				}
+			}
+			chosen = best.Sizes
+		}
+		if !improved {
+			break
+		}
+	}
+	return best, nil
+}
+
+// recommendCandidates returns the explicit candidates, or the most frequent quantities of
+// the sample, sorted ascending. A size equal to a quantity fills that quantity exactly.
+func recommendCandidates(weights map[int]int, explicit []int) ([]int, error) {
+	if len(explicit) > 0 {
+		for _, s := range explicit {
+			if s <= 0 {
+				return nil, fmt.Errorf("%w: got %d", ErrInvalidSize, s)
+			}
+		}
+		return uniqueSizes(explicit), nil
+	}
+
+	candidates := make([]int, 0, len(weights))
+	for q := range weights {
+		candidates = append(candidates, q)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if weights[a] != weights[b] {
+			return weights[a] > weights[b]
+		}
+		return a < b
+	})
+	candidates = candidates[:min(len(candidates), maxCandidates)]
+	sort.Ints(candidates)
+	return candidates, nil
+}
+
+// evaluator scores sets of sizes on the sample, remembering sets it has already seen.
+type evaluator struct {
+	ctx     context.Context
+	solver  Solver
+	weights map[int]int
+	penalty float64
+	seen    map[string]*Recommendation
+}
+
+func (e *evaluator) score(sizes []int) (*Recommendation, error) {
+	sizes = uniqueSizes(sizes)
+	key := fmt.Sprint(sizes)
+	if r, ok := e.seen[key]; ok {
+		return r, nil
+	}
+	if err := e.ctx.Err(); err != nil {
+		return nil, &TimeoutError{Err: err}
+	}
+
+	r := &Recommendation{Sizes: sizes}
+	orders := 0
+	for q, n := range e.weights {
+		_, total, err := e.solver.Solve(e.ctx, q, sizes)
+		if err != nil {
+			return nil, err
+		}
+		r.TotalOverage += (total - q) * n
+		if total == q {
+			r.ExactOrders += n
+		}
+		orders += n
+	}
+	r.MeanOverage = float64(r.TotalOverage) / float64(orders)
+	r.Score = float64(r.TotalOverage) + e.penalty*float64(len(sizes))
+	e.seen[key] = r
+	return r, nil
+}
@@ -0,0 +1,94 @@
+package packsolver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"github.com/stretchr/testify/assert"
+)
+
+// sampleQuantities is a small order history with a few recurring quantities.
+var sampleQuantities = []int{
+	120, 120, 120, 250, 250, 240, 360, 500, 480, 75, 75, 1000, 130, 610, 95, 240, 250, 120,
+}
+
+// bestSubset scores every subset of count candidates and returns the lowest score.
+func bestSubset(t *testing.T, quantities, candidates []int, count int) float64 {
+	best := -1.0
+	var recurse func(start int, chosen []int)
+	recurse = func(start int, chosen []int) {
+		if len(chosen) == count {
+			r, err := packsolver.Recommend(quantities, packsolver.RecommendOptions{Count: count, Candidates: chosen})
+			assert.NoError(t, err)
+			if best < 0 || r.Score < best {
+				best = r.Score
+			}
+			return
+		}
+		for i := start; i < len(candidates); i++ {
+			recurse(i+1, append(append([]int(nil), chosen...), candidates[i]))
+		}
+	}
+	recurse(0, nil)
+	return best
+}
+
+func TestRecommendMatchesExhaustiveSearch(t *testing.T) {
+	candidates := []int{25, 40, 60, 75, 95, 120, 130, 250, 500}
+	for count := 1; count <= 3; count++ {
+		r, err := packsolver.Recommend(sampleQuantities, packsolver.RecommendOptions{Count: count, Candidates: candidates})
+		assert.NoError(t, err)
+		assert.Len(t, r.Sizes, count)
+		assert.Equal(t, bestSubset(t, sampleQuantities, candidates, count), r.Score, "count %d", count)
+	}
+}
+
+func TestRecommendStatistics(t *testing.T) {
+	r, err := packsolver.Recommend([]int{250, 250, 500, 1000, 1001}, packsolver.RecommendOptions{Count: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{250}, r.Sizes)
+	assert.Equal(t, 249, r.TotalOverage)
+	assert.Equal(t, 4, r.ExactOrders)
+	assert.InDelta(t, 49.8, r.MeanOverage, 1e-9)
+	assert.Equal(t, 249.0, r.Score)
+}
+
+func TestRecommendSizePenalty(t *testing.T) {
+	// without a penalty a second size removes the overage of 1001; with a large one it is not worth it
+	r, err := packsolver.Recommend([]int{250, 250, 500, 1000, 1001}, packsolver.RecommendOptions{Count: 3})
+	assert.NoError(t, err)
+	assert.Len(t, r.Sizes, 3)
+
+	r, err = packsolver.Recommend([]int{250, 250, 500, 1000, 1001}, packsolver.RecommendOptions{Count: 3, SizePenalty: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{250}, r.Sizes)
+	assert.Equal(t, 1249.0, r.Score)
+}
+
+func TestRecommendUsesGivenSolver(t *testing.T) {
+	solver, ok := packsolver.Lookup(packsolver.StrategyGreedy)
+	assert.True(t, ok)
+	r, err := packsolver.Recommend(sampleQuantities, packsolver.RecommendOptions{Count: 2, Solver: solver})
+	assert.NoError(t, err)
+	assert.Len(t, r.Sizes, 2)
+}
+
+func TestRecommendInvalidInput(t *testing.T) {
+	_, err := packsolver.Recommend(nil, packsolver.RecommendOptions{Count: 1})
+	assert.ErrorIs(t, err, packsolver.ErrInvalidQuantity)
+	_, err = packsolver.Recommend([]int{10, 0}, packsolver.RecommendOptions{Count: 1})
+	assert.ErrorIs(t, err, packsolver.ErrInvalidQuantity)
+	_, err = packsolver.Recommend([]int{10}, packsolver.RecommendOptions{})
+	assert.ErrorIs(t, err, packsolver.ErrInvalidCount)
+	_, err = packsolver.Recommend([]int{10}, packsolver.RecommendOptions{Count: 1, SizePenalty: -1})
+	assert.ErrorIs(t, err, packsolver.ErrInvalidPenalty)
+	_, err = packsolver.Recommend([]int{10}, packsolver.RecommendOptions{Count: 1, Candidates: []int{5, -5}})
+	assert.ErrorIs(t, err, packsolver.ErrInvalidSize)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = packsolver.RecommendContext(ctx, sampleQuantities, packsolver.RecommendOptions{Count: 2})
+	var timeoutErr *packsolver.TimeoutError
+	assert.ErrorAs(t, err, &timeoutErr)
+}
@@ -0,0 +1,80 @@
+package packsolver
+
+import (
+	"context"
+	"fmt"
+)
+
+// Kinds of redundant pack sizes.
+const (
+	RedundantMultiple    = "multiple"    // the size is a multiple of a single smaller size
+	RedundantCombination = "combination" // the size is a sum of several smaller sizes
+)
+
+// Redundancy describes a pack size that smaller sizes can replace. Such a size never
+// lowers the number of items shipped for any quantity, it only saves packs, so it is
+// redundant only where the number of packs does not matter.
+type Redundancy struct {
+	Size        int          `json:"size"`
+	Kind        string       `json:"kind"`        // RedundantMultiple or RedundantCombination
+	Replacement []PackResult `json:"replacement"` // smaller packs adding up to Size
+}
+
+// Reason returns a human-readable explanation of the redundancy.
+func (r Redundancy) Reason() string {
+	if r.Kind == RedundantMultiple {
+		return fmt.Sprintf("size %d is %d × %d; it never reduces the items shipped, only the number of packs",
+			r.Size, r.Replacement[0].Count, r.Replacement[0].Size)
+	}
+	return fmt.Sprintf("size %d can be made of %v; it never reduces the items shipped, only the number of packs",
+		r.Size, r.Replacement)
+}
+
+// FindRedundantSizes returns the sizes that the other sizes can replace, in ascending order.
+// Any total a redundant size helps to reach can be reached without it, so all of them can be
+// dropped together without changing which quantities are filled exactly.
+//
+// The analysis looks at items only. Under the objective of SolvePackDistribution no size is
+// ever redundant: for a quantity equal to the size it is the only answer with a single pack.
+func FindRedundantSizes(sizes []int) ([]Redundancy, error) {
+	return FindRedundantSizesContext(context.Background(), sizes)
+}
+
+// FindRedundantSizesContext is FindRedundantSizes that stops with a *TimeoutError once ctx is done.
+//
+// Only smaller sizes can add up to a size, so each size is solved exactly with the ones
+// below it by the branch-and-bound solver, whose cost does not depend on the size itself.
+func FindRedundantSizesContext(ctx context.Context, sizes []int) ([]Redundancy, error) {
+	if err := validate(1, sizes); err != nil { // only the sizes matter here
+		return nil, err
+	}
+
+	sizes = uniqueSizes(sizes)
+	var found []Redundancy
+	for i, size := range sizes {
+		// A multiple of the largest smaller size that divides it is the replacement with the fewest packs
+		multiple := false
+		for j := i - 1; j >= 0 && !multiple; j-- {
+			if size%sizes[j] == 0 {
+				found = append(found, Redundancy{
+					Size:        size,
+					Kind:        RedundantMultiple,
+					Replacement: []PackResult{{Size: sizes[j], Count: size / sizes[j]}},
+				})
+				multiple = true
+			}
+		}
+		if multiple || i < 2 { // a single smaller size can only replace a multiple of itself
+			continue
+		}
+
+		packs, total, err := SolveBranchAndBoundContext(ctx, size, sizes[:i])
+		if err != nil {
+			return nil, err
+		}
+		if total == size {
+			found = append(found, Redundancy{Size: size, Kind: RedundantCombination, Replacement: packs})
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,71 @@
+package packsolver_test
+
+import (
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindRedundantSizes(t *testing.T) {
+	found, err := packsolver.FindRedundantSizes([]int{1000, 250, 750, 500, 333})
+	assert.NoError(t, err)
+	assert.Equal(t, []packsolver.Redundancy{
+		{Size: 500, Kind: packsolver.RedundantMultiple, Replacement: []packsolver.PackResult{{Size: 250, Count: 2}}},
+		{Size: 750, Kind: packsolver.RedundantMultiple, Replacement: []packsolver.PackResult{{Size: 250, Count: 3}}},
+		{Size: 1000, Kind: packsolver.RedundantMultiple, Replacement: []packsolver.PackResult{{Size: 500, Count: 2}}},
+	}, found)
+
+	found, err = packsolver.FindRedundantSizes([]int{6, 9, 15, 20})
+	assert.NoError(t, err)
+	assert.Equal(t, []packsolver.Redundancy{
+		{Size: 15, Kind: packsolver.RedundantCombination, Replacement: []packsolver.PackResult{{Size: 6, Count: 1}, {Size: 9, Count: 1}}},
+	}, found)
+	assert.Contains(t, found[0].Reason(), "size 15 can be made of")
+
+	found, err = packsolver.FindRedundantSizes([]int{23, 31, 53})
+	assert.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestRedundantSizesKeepExactQuantities(t *testing.T) {
+	sizes := []int{4, 6, 10, 14, 15, 21}
+	found, err := packsolver.FindRedundantSizes(sizes)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, found)
+
+	var kept []int
+	for _, s := range sizes {
+		redundant := false
+		for _, r := range found {
+			redundant = redundant || r.Size == s
+		}
+		if !redundant {
+			kept = append(kept, s)
+		}
+	}
+
+	// dropping every redundant size at once never changes the items shipped
+	for quantity := 1; quantity <= 500; quantity++ {
+		_, all, err := packsolver.SolvePackDistribution(quantity, sizes)
+		assert.NoError(t, err)
+		_, reduced, err := packsolver.SolvePackDistribution(quantity, kept)
+		assert.NoError(t, err)
+		assert.Equal(t, all, reduced, "quantity %d", quantity)
+	}
+}
+
+func TestRedundantSizesStillSavePacks(t *testing.T) {
+	sizes := []int{250, 500, 1000, 2000, 5000}
+	found, err := packsolver.FindRedundantSizes(sizes)
+	assert.NoError(t, err)
+	assert.Len(t, found, 4)
+
+	// a redundant size is still the only single-pack answer for its own quantity
+	for _, r := range found {
+		packs, total, err := packsolver.SolvePackDistribution(r.Size, sizes)
+		assert.NoError(t, err)
+		assert.Equal(t, r.Size, total)
+		assert.Equal(t, []packsolver.PackResult{{Size: r.Size, Count: 1}}, packs)
+	}
+}
@@ -0,0 +1,88 @@
+package packsolver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Names of the built-in solving strategies.
+const (
+	StrategyGreedy = "greedy"
+	StrategyDP     = "dp"
+	StrategyDFS    = "dfs"
+	StrategySmart  = "smart"
+	StrategyBnB    = "bnb"
+
+	// DefaultStrategy is used when the caller does not ask for a specific one.
+	DefaultStrategy = StrategySmart
+)
+
+// Solver computes a pack distribution covering the requested quantity.
+// Implementations should return a *TimeoutError once ctx is done.
+type Solver interface {
+	Solve(ctx context.Context, quantity int, sizes []int) ([]PackResult, int, error)
+}
+
+// SolverFunc adapts a plain solving function to the Solver interface.
+type SolverFunc func(ctx context.Context, quantity int, sizes []int) ([]PackResult, int, error)
+
+// Solve calls f(ctx, quantity, sizes).
+func (f SolverFunc) Solve(ctx context.Context, quantity int, sizes []int) ([]PackResult, int, error) {
+	return f(ctx, quantity, sizes)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Solver{
+		StrategyGreedy: SolverFunc(SolveGreedyContext),
+		StrategyDP:     SolverFunc(SolvePackDistributionContext),
+		StrategyDFS:    SolverFunc(SolvePackDistribution2Context),
+		StrategySmart:  SolverFunc(SolveSmartContext),
+		StrategyBnB:    SolverFunc(SolveBranchAndBoundContext),
+	}
+)
+
+// Register makes a solver available under the given name, so that strategies defined
+// outside this package can be selected the same way as the built-in ones.
+// It fails if the name is empty, the solver is nil or the name is already taken.
+func Register(name string, s Solver) error {
+	if name == "" {
+		return fmt.Errorf("solver name must not be empty")
+	}
+	if s == nil {
+		return fmt.Errorf("solver %q is nil", name)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		return fmt.Errorf("solver %q is already registered", name)
+	}
+	registry[name] = s
+	return nil
+}
+
+// Lookup returns the solver registered under the given name.
+func Lookup(name string) (Solver, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	s, ok := registry[name]
+	return s, ok
+}
+
+// Strategies returns the names of all registered solvers in alphabetical order.
+func Strategies() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,54 @@
+package packsolver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltInStrategies(t *testing.T) {
+	sizes := []int{250, 500, 1000}
+
+	for _, name := range []string{"greedy", "dp", "dfs", "smart"} {
+		solver, ok := packsolver.Lookup(name)
+		assert.True(t, ok, name)
+
+		packs, total, err := solver.Solve(context.Background(), 1250, sizes)
+		assert.NoError(t, err, name)
+		assert.Equal(t, 1250, total, name)
+		assert.NotEmpty(t, packs, name)
+	}
+
+	_, ok := packsolver.Lookup(packsolver.DefaultStrategy)
+	assert.True(t, ok)
+}
+
+func TestRegisterCustomStrategy(t *testing.T) {
+	// always ships a single pack of the first size
+	single := packsolver.SolverFunc(func(_ context.Context, quantity int, sizes []int) ([]packsolver.PackResult, int, error) {
+		return []packsolver.PackResult{{Size: sizes[0], Count: 1}}, sizes[0], nil
+	})
+
+	err := packsolver.Register("test-single", single)
+	assert.NoError(t, err)
+	assert.Contains(t, packsolver.Strategies(), "test-single")
+
+	solver, ok := packsolver.Lookup("test-single")
+	assert.True(t, ok)
+	packs, total, err := solver.Solve(context.Background(), 10, []int{42})
+	assert.NoError(t, err)
+	assert.Equal(t, 42, total)
+	assert.Equal(t, []packsolver.PackResult{{Size: 42, Count: 1}}, packs)
+
+	assert.Error(t, packsolver.Register("test-single", single))
+	assert.Error(t, packsolver.Register("smart", single))
+	assert.Error(t, packsolver.Register("", single))
+	assert.Error(t, packsolver.Register("test-nil", nil))
+}
+
+func TestLookupUnknownStrategy(t *testing.T) {
+	_, ok := packsolver.Lookup("does-not-exist")
+	assert.False(t, ok)
+}
@@ -1,32 +1,74 @@
 package packsolver
 
 import (
+	"context"
 	"math"
 	"sort"
 )
 
+// cancelCheckInterval is how many inner loop iterations the solvers run between two
+// context checks; checking on every iteration would dominate the DP's runtime.
+const cancelCheckInterval = 1 << 12
+
 // PackResult represents one pack size and the number of times it's used.
 type PackResult struct {
 	Size  int `json:"size"`  // size of the pack
 	Count int `json:"count"` // how many times this pack is used
 }
 
-// SolveSmart runs greedy and DP and picks the better result based on minimal total.
-func SolveSmart(quantity int, sizes []int) ([]PackResult, int) {
-	greedy, gTotal := SolveGreedy(quantity, sizes)
-	dp, dTotal := SolvePackDistribution(quantity, sizes)
+// SolveSmart runs greedy and DP and picks the better result based on minimal total,
+// then on the minimal number of packs.
+func SolveSmart(quantity int, sizes []int) ([]PackResult, int, error) {
+	return SolveSmartContext(context.Background(), quantity, sizes)
+}
+
+// SolveSmartContext is SolveSmart that stops with a *TimeoutError once ctx is done.
+func SolveSmartContext(ctx context.Context, quantity int, sizes []int) ([]PackResult, int, error) {
+	greedy, gTotal, err := SolveGreedyContext(ctx, quantity, sizes)
+	if err != nil {
+		return nil, 0, err
+	}
+	dp, dTotal, err := SolvePackDistributionContext(ctx, quantity, sizes)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if Better(gTotal, TotalPacks(greedy), dTotal, TotalPacks(dp)) {
+		return greedy, gTotal, nil
+	}
+	return dp, dTotal, nil
+}
+
+// TotalPacks returns the number of packs used by the given distribution.
+func TotalPacks(packs []PackResult) int {
+	n := 0
+	for _, p := range packs {
+		n += p.Count
+	}
+	return n
+}
 
-	if dTotal <= gTotal {
-		return dp, dTotal
+// Better reports whether a solution with aTotal items in aPacks packs is strictly better
+// than one with bTotal items in bPacks packs: fewer items first, then fewer packs.
+func Better(aTotal, aPacks, bTotal, bPacks int) bool {
+	if aTotal != bTotal {
+		return aTotal < bTotal
 	}
-	return greedy, gTotal
+	return aPacks < bPacks
 }
 
 // SolvePackDistribution uses dynamic programming to find the minimal total quantity of packs
-// whose sum is equal or greater than the requested quantity.
-func SolvePackDistribution(quantity int, sizes []int) ([]PackResult, int) {
-	if len(sizes) == 0 || quantity <= 0 {
-		return []PackResult{}, 0
+// whose sum is equal or greater than the requested quantity. Among the combinations reaching
+// that total it returns the one with the fewest packs.
+func SolvePackDistribution(quantity int, sizes []int) ([]PackResult, int, error) {
+	return SolvePackDistributionContext(context.Background(), quantity, sizes)
+}
+
+// SolvePackDistributionContext is SolvePackDistribution that checks ctx while filling the
+// DP table and stops with a *TimeoutError once it is done.
+func SolvePackDistributionContext(ctx context.Context, quantity int, sizes []int) ([]PackResult, int, error) {
+	if err := validate(quantity, sizes); err != nil {
+		return nil, 0, err
 	}
 
 	// Find the largest pack size to set DP search limit
@@ -37,29 +79,35 @@ func SolvePackDistribution(quantity int, sizes []int) ([]PackResult, int) {
 		}
 	}
 
-	limit := quantity + maxSize         // allow room for small overage
-	dp := make([]int, limit+1)          // dp[i] = min total for i
-	packCount := make([][]int, limit+1) // packCount[i] = how many of each size for dp[i]
+	// Only the pack count and the last pack used are kept per total, so memory stays
+	// O(quantity) regardless of how many sizes are configured. The distribution is
+	// rebuilt afterwards by walking last[] back to zero.
+	limit := quantity + maxSize    // allow room for small overage
+	dp := make([]int32, limit+1)   // dp[i] = min number of packs summing exactly to i
+	last := make([]int32, limit+1) // last[i] = index of the size added last to reach i
 
 	for i := 1; i <= limit; i++ {
 		dp[i] = math.MaxInt32
 	}
 	dp[0] = 0
-	packCount[0] = make([]int, len(sizes))
 
 	for i := 1; i <= limit; i++ {
+		if i%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, 0, &TimeoutError{Err: err}
+			}
+		}
 		for j, size := range sizes {
 			if i >= size && dp[i-size] != math.MaxInt32 {
-				if dp[i] > dp[i-size]+size {
-					dp[i] = dp[i-size] + size
-					packCount[i] = append([]int(nil), packCount[i-size]...)
-					packCount[i][j]++
+				if dp[i] > dp[i-size]+1 {
+					dp[i] = dp[i-size] + 1
+					last[i] = int32(j)
 				}
 			}
 		}
 	}
 
-	// Find first valid solution >= quantity
+	// Find first valid solution >= quantity; dp already holds its minimal pack count
 	bestTotal := -1
 	for i := quantity; i <= limit; i++ {
 		if dp[i] != math.MaxInt32 {
@@ -68,11 +116,17 @@ func SolvePackDistribution(quantity int, sizes []int) ([]PackResult, int) {
 		}
 	}
 	if bestTotal == -1 {
-		return []PackResult{}, 0
+		return nil, 0, ErrUnreachable
+	}
+
+	// Backtrack from bestTotal, counting how often each size was used
+	counts := make([]int, len(sizes))
+	for i := bestTotal; i > 0; i -= sizes[last[i]] {
+		counts[last[i]]++
 	}
 
 	var result []PackResult
-	for i, count := range packCount[bestTotal] {
+	for i, count := range counts {
 		if count > 0 {
 			result = append(result, PackResult{
 				Size:  sizes[i],
@@ -81,11 +135,26 @@ func SolvePackDistribution(quantity int, sizes []int) ([]PackResult, int) {
 		}
 	}
 
-	return result, bestTotal
+	return result, bestTotal, nil
 }
 
 // SolveGreedy prefers large packs first, then adjusts to match the quantity.
-func SolveGreedy(quantity int, sizes []int) ([]PackResult, int) {
+func SolveGreedy(quantity int, sizes []int) ([]PackResult, int, error) {
+	return SolveGreedyContext(context.Background(), quantity, sizes)
+}
+
+// SolveGreedyContext is SolveGreedy with the same signature as the other context-aware
+// solvers. Greedy runs in O(len(sizes)), so ctx is only checked once up front.
+func SolveGreedyContext(ctx context.Context, quantity int, sizes []int) ([]PackResult, int, error) {
+	if err := validate(quantity, sizes); err != nil {
+		return nil, 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, 0, &TimeoutError{Err: err}
+	}
+
+	// Sort a copy so that callers sharing the slice (e.g. through the registry) are not affected
+	sizes = append([]int(nil), sizes...)
 	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
 	remaining := quantity
 	packMap := make(map[int]int)
@@ -121,7 +190,7 @@ func SolveGreedy(quantity int, sizes []int) ([]PackResult, int) {
 		}
 	}
 
-	return results, total
+	return results, total, nil
 }
 
 // SolvePackDistribution2 attempts to find the combination of pack sizes that
@@ -134,40 +203,80 @@ func SolveGreedy(quantity int, sizes []int) ([]PackResult, int) {
 // Returns:
 // - slice of PackResult (each containing Size and Count)
 // - total number of packed items (which may be slightly more than quantity)
+// - ErrInvalidQuantity, ErrNoPackSizes or ErrInvalidSize for invalid input
 //
 // Strategy:
 // - uses a depth-first search (DFS) to try all combinations
 // - explores combinations recursively
 // - minimizes total packed items (not number of packs)
-func SolvePackDistribution2(quantity int, sizes []int) ([]PackResult, int) {
+func SolvePackDistribution2(quantity int, sizes []int) ([]PackResult, int, error) {
+	return SolvePackDistribution2Context(context.Background(), quantity, sizes)
+}
+
+// SolvePackDistribution2Context is SolvePackDistribution2 that checks ctx while exploring
+// combinations and stops with a *TimeoutError once it is done. The search is exponential,
+// so callers serving requests should always pass a context with a deadline.
+func SolvePackDistribution2Context(ctx context.Context, quantity int, sizes []int) ([]PackResult, int, error) {
+	if err := validate(quantity, sizes); err != nil {
+		return nil, 0, err
+	}
+
+	minCount := make([]int, len(sizes))
+	maxCount := make([]int, len(sizes))
+	for j := range maxCount {
+		maxCount[j] = -1
+	}
+	return solveDFS(ctx, quantity, sizes, minCount, maxCount, -1)
+}
+
+// solveDFS runs the depth-first search of SolvePackDistribution2 using between
+// minCount[j] and maxCount[j] (-1 for unlimited) packs of sizes[j] and at most
+// maxPacks packs in total (-1 for no cap).
+func solveDFS(ctx context.Context, quantity int, sizes []int, minCount, maxCount []int, maxPacks int) ([]PackResult, int, error) {
 	var best []PackResult          // best combination found so far
 	minTotal := int(^uint(0) >> 1) // set to MaxInt
+	var ctxErr error               // set once ctx is done to unwind the recursion
+	calls := 0
 
 	// recurse is a recursive DFS function to explore combinations
-	var recurse func(index, remaining, currentTotal int, current []PackResult)
-	recurse = func(index, remaining, currentTotal int, current []PackResult) {
-		// Base case: if remaining is <= 0, we found a valid or overfilled combo
-		if remaining <= 0 {
-			if currentTotal < minTotal {
-				minTotal = currentTotal
-				best = make([]PackResult, len(current))
-				copy(best, current)
-			}
+	var recurse func(index, remaining, currentTotal, packs int, current []PackResult)
+	recurse = func(index, remaining, currentTotal, packs int, current []PackResult) {
+		if ctxErr != nil {
 			return
 		}
+		calls++
+		if calls%cancelCheckInterval == 0 {
+			if ctxErr = ctx.Err(); ctxErr != nil {
+				return
+			}
+		}
 
-		// If we’ve used all pack sizes and still not satisfied quantity
+		// Base case: once every pack size is decided, keep a valid or overfilled combo
+		// (sizes after the quantity is covered still have to meet their minimum)
 		if index == len(sizes) {
+			if remaining <= 0 && currentTotal < minTotal {
+				minTotal = currentTotal
+				best = make([]PackResult, len(current))
+				copy(best, current)
+			}
 			return
 		}
 
 		packSize := sizes[index]
 
-		// maxCount = how many times we can use this packSize without going too far
-		maxCount := (remaining + packSize - 1) / packSize // ceil division
+		// upper = how many times we can use this packSize without going too far
+		// (ceil division), but never fewer than the minimum nor more than the maximum
+		upper := max(minCount[index], (remaining+packSize-1)/packSize)
+		if maxCount[index] >= 0 {
+			upper = min(upper, maxCount[index])
+		}
+
+		// Try using this pack size from its minimum up to upper times
+		for count := minCount[index]; count <= upper && ctxErr == nil; count++ {
+			if maxPacks >= 0 && packs+count > maxPacks {
+				break
+			}
 
-		// Try using this pack size from 0 up to maxCount times
-		for count := 0; count <= maxCount; count++ {
 			// Create a fresh copy of the current path (to preserve state)
 			next := append([]PackResult{}, current...)
 
@@ -177,16 +286,19 @@ func SolvePackDistribution2(quantity int, sizes []int) ([]PackResult, int) {
 			}
 
 			// Recurse to the next pack size
-			recurse(index+1, remaining-count*packSize, currentTotal+count*packSize, next)
+			recurse(index+1, remaining-count*packSize, currentTotal+count*packSize, packs+count, next)
 		}
 	}
 
 	// Start DFS from index 0
-	recurse(0, quantity, 0, []PackResult{})
+	recurse(0, quantity, 0, 0, []PackResult{})
 
+	if ctxErr != nil {
+		return nil, 0, &TimeoutError{Err: ctxErr}
+	}
 	if best == nil {
-		return []PackResult{}, 0
+		return nil, 0, ErrUnreachable
 	}
 
-	return best, minTotal
+	return best, minTotal, nil
 }
@@ -2,16 +2,19 @@
 package packsolver_test
 
 import (
+	"context"
 	"fmt"
 	"github.com/rapido-liebre/pack_solver/internal/packsolver"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestExactMatch(t *testing.T) {
 	sizes := []int{250, 500, 1000}
-	packs, total := packsolver.SolvePackDistribution(2000, sizes)
+	packs, total, err := packsolver.SolvePackDistribution(2000, sizes)
+	assert.NoError(t, err)
 	expected := 2000
 	assert.Equal(t, expected, total)
 	assert.GreaterOrEqual(t, len(packs), 1)
@@ -19,28 +22,32 @@ func TestExactMatch(t *testing.T) {
 
 func TestMinimalExcess(t *testing.T) {
 	sizes := []int{250, 500, 1000}
-	packs, total := packsolver.SolvePackDistribution(2300, sizes)
+	packs, total, err := packsolver.SolvePackDistribution(2300, sizes)
+	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, total, 2300)
 	assert.NotEmpty(t, packs)
 }
 
 func TestZeroQuantity(t *testing.T) {
 	sizes := []int{250, 500, 1000}
-	packs, total := packsolver.SolvePackDistribution(0, sizes)
+	packs, total, err := packsolver.SolvePackDistribution(0, sizes)
+	assert.ErrorIs(t, err, packsolver.ErrInvalidQuantity)
 	assert.Equal(t, 0, total)
 	assert.Empty(t, packs)
 }
 
 func TestNoSizesAvailable(t *testing.T) {
 	sizes := []int{}
-	packs, total := packsolver.SolvePackDistribution(1000, sizes)
+	packs, total, err := packsolver.SolvePackDistribution(1000, sizes)
+	assert.ErrorIs(t, err, packsolver.ErrNoPackSizes)
 	assert.Equal(t, 0, total)
 	assert.Empty(t, packs)
 }
 
 func TestLargeQuantity(t *testing.T) {
 	sizes := []int{250, 500, 1000, 2000, 5000}
-	packs, total := packsolver.SolvePackDistribution(12345, sizes)
+	packs, total, err := packsolver.SolvePackDistribution(12345, sizes)
+	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, total, 12345)
 	assert.NotEmpty(t, packs)
 }
@@ -48,7 +55,8 @@ func TestLargeQuantity(t *testing.T) {
 func TestSolvePackDistribution(t *testing.T) {
 	sizes := []int{100, 250, 500, 1000}
 	quantity := 12001
-	packs, total := packsolver.SolvePackDistribution(quantity, sizes)
+	packs, total, err := packsolver.SolvePackDistribution(quantity, sizes)
+	assert.NoError(t, err)
 	assert.NotEmpty(t, packs)
 	assert.GreaterOrEqual(t, total, quantity)
 
@@ -63,7 +71,8 @@ func TestVeryLargeQuantity(t *testing.T) {
 	sizes := []int{23, 31, 53}
 	quantity := 500000
 
-	packs, total := packsolver.SolveSmart(quantity, sizes)
+	packs, total, err := packsolver.SolveSmart(quantity, sizes)
+	assert.NoError(t, err)
 
 	assert.NotEmpty(t, packs)
 	assert.GreaterOrEqual(t, total, quantity)
@@ -79,9 +88,12 @@ func TestCompareAllStrategies(t *testing.T) {
 	sizes := []int{23, 31, 53}
 	quantity := 500000
 
-	smartPacks, smartTotal := packsolver.SolveSmart(quantity, sizes)
-	dpPacks, dpTotal := packsolver.SolvePackDistribution(quantity, sizes)
-	greedyPacks, greedyTotal := packsolver.SolveGreedy(quantity, sizes)
+	smartPacks, smartTotal, err := packsolver.SolveSmart(quantity, sizes)
+	assert.NoError(t, err)
+	dpPacks, dpTotal, err := packsolver.SolvePackDistribution(quantity, sizes)
+	assert.NoError(t, err)
+	greedyPacks, greedyTotal, err := packsolver.SolveGreedy(quantity, sizes)
+	assert.NoError(t, err)
 
 	fmt.Println("=== Smart Strategy ===")
 	for _, p := range smartPacks {
@@ -105,3 +117,144 @@ func TestCompareAllStrategies(t *testing.T) {
 	assert.GreaterOrEqual(t, dpTotal, quantity)
 	assert.GreaterOrEqual(t, greedyTotal, quantity)
 }
+
+// bruteForce enumerates every combination of packs whose total stays below
+// quantity+max(sizes) and returns the best total and pack count, comparing
+// lexicographically by items and then by number of packs.
+func bruteForce(quantity int, sizes []int) (int, int) {
+	maxSize := 0
+	for _, s := range sizes {
+		maxSize = max(maxSize, s)
+	}
+	limit := quantity + maxSize
+
+	bestTotal, bestPacks := -1, 0
+	var recurse func(index, total, packs int)
+	recurse = func(index, total, packs int) {
+		if index == len(sizes) {
+			if total >= quantity && (bestTotal == -1 || packsolver.Better(total, packs, bestTotal, bestPacks)) {
+				bestTotal, bestPacks = total, packs
+			}
+			return
+		}
+		for count := 0; total+count*sizes[index] <= limit; count++ {
+			recurse(index+1, total+count*sizes[index], packs+count)
+		}
+	}
+	recurse(0, 0, 0)
+	return bestTotal, bestPacks
+}
+
+func TestFewestPacksAmongMinimalTotals(t *testing.T) {
+	// 600 can be reached with 6x100, 3x200 or 1x600; only the last is acceptable
+	sizes := []int{100, 200, 600}
+	packs, total, err := packsolver.SolvePackDistribution(600, sizes)
+	assert.NoError(t, err)
+	assert.Equal(t, 600, total)
+	assert.Equal(t, []packsolver.PackResult{{Size: 600, Count: 1}}, packs)
+}
+
+func TestLexicographicObjectiveMatchesBruteForce(t *testing.T) {
+	sizeSets := [][]int{
+		{250, 500, 1000},
+		{3, 5, 7},
+		{1, 4, 6, 9},
+		{6, 10, 15},
+		{2, 3, 12, 13},
+		{23, 31, 53},
+	}
+
+	for _, sizes := range sizeSets {
+		for quantity := 1; quantity <= 120; quantity++ {
+			wantTotal, wantPacks := bruteForce(quantity, sizes)
+
+			dpPacks, dpTotal, err := packsolver.SolvePackDistribution(quantity, sizes)
+			assert.NoError(t, err)
+			assert.Equal(t, wantTotal, dpTotal, "dp total for %d with %v", quantity, sizes)
+			assert.Equal(t, wantPacks, packsolver.TotalPacks(dpPacks), "dp packs for %d with %v", quantity, sizes)
+
+			smartPacks, smartTotal, err := packsolver.SolveSmart(quantity, append([]int(nil), sizes...))
+			assert.NoError(t, err)
+			assert.Equal(t, wantTotal, smartTotal, "smart total for %d with %v", quantity, sizes)
+			assert.Equal(t, wantPacks, packsolver.TotalPacks(smartPacks), "smart packs for %d with %v", quantity, sizes)
+		}
+	}
+}
+
+func TestSolvePackDistributionAllocations(t *testing.T) {
+	sizes := []int{250, 500, 1000, 2000, 5000}
+	quantity := 1_000_001
+
+	// The DP keeps flat per-total tables instead of a count vector per cell,
+	// so the number of allocations must not grow with the quantity.
+	allocs := testing.AllocsPerRun(1, func() {
+		packs, total, err := packsolver.SolvePackDistribution(quantity, sizes)
+		assert.NoError(t, err)
+		assert.Equal(t, 1_000_250, total)
+		assert.NotEmpty(t, packs)
+	})
+	assert.Less(t, allocs, 20.0)
+}
+
+func TestContextSolversCancelled(t *testing.T) {
+	sizes := []int{250, 500, 1000}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := packsolver.SolvePackDistributionContext(ctx, 10_000_000, sizes)
+	var timeoutErr *packsolver.TimeoutError
+	assert.ErrorAs(t, err, &timeoutErr)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, _, err = packsolver.SolveSmartContext(ctx, 10_000_000, sizes)
+	assert.ErrorAs(t, err, &timeoutErr)
+}
+
+func TestDFSStopsAtDeadline(t *testing.T) {
+	// exhaustive DFS over these sizes would run for a very long time
+	sizes := []int{1, 2, 3, 5, 7}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err := packsolver.SolvePackDistribution2Context(ctx, 100_000, sizes)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestContextSolversMatchPlainSolvers(t *testing.T) {
+	sizes := []int{23, 31, 53}
+	packs, total, err := packsolver.SolvePackDistributionContext(context.Background(), 1000, sizes)
+	assert.NoError(t, err)
+
+	plainPacks, plainTotal, err := packsolver.SolvePackDistribution(1000, sizes)
+	assert.NoError(t, err)
+	assert.Equal(t, plainTotal, total)
+	assert.Equal(t, plainPacks, packs)
+}
+
+func TestSolversReturnTypedErrors(t *testing.T) {
+	solvers := map[string]func(int, []int) ([]packsolver.PackResult, int, error){
+		"smart":  packsolver.SolveSmart,
+		"dp":     packsolver.SolvePackDistribution,
+		"greedy": packsolver.SolveGreedy,
+		"dfs":    packsolver.SolvePackDistribution2,
+	}
+
+	for name, solve := range solvers {
+		_, _, err := solve(0, []int{250})
+		assert.ErrorIs(t, err, packsolver.ErrInvalidQuantity, name)
+
+		_, _, err = solve(-10, []int{250})
+		assert.ErrorIs(t, err, packsolver.ErrInvalidQuantity, name)
+
+		_, _, err = solve(100, nil)
+		assert.ErrorIs(t, err, packsolver.ErrNoPackSizes, name)
+
+		_, _, err = solve(100, []int{250, 0})
+		assert.ErrorIs(t, err, packsolver.ErrInvalidSize, name)
+
+		_, _, err = solve(100, []int{-5})
+		assert.ErrorIs(t, err, packsolver.ErrInvalidSize, name)
+	}
+}
@@ -0,0 +1,179 @@
+package packsolver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"sync"
+)
+
+// ErrTableTooLarge is returned by NewTable when the periodic bound of the sizes is so
+// large that precomputing it would cost more memory than solving each order directly.
+var ErrTableTooLarge = errors.New("answer table would be too large for these pack sizes")
+
+// maxTableEntries caps the number of totals a Table precomputes (8 bytes each).
+const maxTableEntries = 1 << 22
+
+// Table answers the same queries as SolvePackDistribution (fewest items, then fewest packs)
+// for one fixed set of pack sizes, in time independent of the quantity.
+//
+// All sizes are divided by their GCD g first, since every shippable total is a multiple of g.
+// With L the largest reduced size and S the second largest, every quantity above
+// bound = (L-1)·S can be shipped exactly, and its fewest-pack distribution contains an L-pack:
+// a distribution using L or more smaller packs always has a subset summing to a multiple of
+// L, which fewer L-packs could replace. Answers for larger quantities are therefore the
+// answer for a quantity in (bound, bound+L] plus as many L-packs as needed.
+type Table struct {
+	sizes   []int   // unique sizes, ascending
+	gcd     int     // greatest common divisor of sizes
+	reduced []int   // sizes / gcd
+	bound   int     // reduced quantities above bound follow the periodic pattern
+	best    []int32 // best[q] = smallest reachable reduced total >= q, for q <= bound+L
+	last    []int32 // last[t] = index of the size added last to reach reduced total t
+}
+
+// NewTable precomputes the answers for the given pack sizes.
+func NewTable(sizes []int) (*Table, error) {
+	return NewTableContext(context.Background(), sizes)
+}
+
+// NewTableContext is NewTable that stops with a *TimeoutError once ctx is done.
+func NewTableContext(ctx context.Context, sizes []int) (*Table, error) {
+	if err := validate(1, sizes); err != nil { // only the sizes matter here
+		return nil, err
+	}
+
+	t := &Table{sizes: uniqueSizes(sizes)}
+	t.gcd = t.sizes[0]
+	for _, s := range t.sizes[1:] {
+		t.gcd = gcd(t.gcd, s)
+	}
+	for _, s := range t.sizes {
+		t.reduced = append(t.reduced, s/t.gcd)
+	}
+
+	n := len(t.reduced)
+	largest := t.reduced[n-1]
+	if n > 1 {
+		if int64(largest-1)*int64(t.reduced[n-2]) > maxTableEntries {
+			return nil, ErrTableTooLarge
+		}
+		t.bound = (largest - 1) * t.reduced[n-2]
+	}
+
+	// Fill the fewest-pack DP for every reduced total up to bound+2L, so that every
+	// quantity up to bound+L finds its best total within the table
+	top := t.bound + 2*largest
+	if top > maxTableEntries {
+		return nil, ErrTableTooLarge
+	}
+	packs := make([]int32, top+1)
+	t.last = make([]int32, top+1)
+	for i := 1; i <= top; i++ {
+		packs[i] = math.MaxInt32
+		if i%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, &TimeoutError{Err: err}
+			}
+		}
+		for j, size := range t.reduced {
+			if i >= size && packs[i-size] != math.MaxInt32 && packs[i-size]+1 < packs[i] {
+				packs[i] = packs[i-size] + 1
+				t.last[i] = int32(j)
+			}
+		}
+	}
+
+	// best[q] is the first reachable total at or above q, found scanning downwards
+	t.best = make([]int32, t.bound+largest+1)
+	next := int32(-1)
+	for i := top; i >= 0; i-- {
+		if packs[i] != math.MaxInt32 {
+			next = int32(i)
+		}
+		if i < len(t.best) {
+			t.best[i] = next
+		}
+	}
+	return t, nil
+}
+
+// Solve returns the best distribution for the quantity, listing sizes in ascending order.
+func (t *Table) Solve(quantity int) ([]PackResult, int, error) {
+	if quantity <= 0 {
+		return nil, 0, ErrInvalidQuantity
+	}
+
+	n := len(t.reduced)
+	largest := t.reduced[n-1]
+	q := (quantity + t.gcd - 1) / t.gcd // reduced quantity, rounded up to a shippable multiple
+
+	// Above bound+L strip whole L-packs until the quantity falls into the table
+	extra := 0
+	if limit := t.bound + largest; q > limit {
+		extra = (q - limit + largest - 1) / largest
+		q -= extra * largest
+	}
+
+	counts := make([]int, n)
+	counts[n-1] = extra
+	total := int(t.best[q])
+	for i := total; i > 0; i -= t.reduced[t.last[i]] {
+		counts[t.last[i]]++
+	}
+	total += extra * largest
+
+	var result []PackResult
+	for j, count := range counts {
+		if count > 0 {
+			result = append(result, PackResult{Size: t.sizes[j], Count: count})
+		}
+	}
+	return result, total * t.gcd, nil
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// TableCache keeps the Table of the most recently used pack configuration, so that it is
+// only rebuilt when the sizes change. It is safe for concurrent use.
+type TableCache struct {
+	mu    sync.Mutex
+	sizes []int
+	table *Table
+	err   error
+}
+
+// Get returns the table for the given sizes, building it if the cached one was made for
+// different sizes or was invalidated. ErrTableTooLarge is cached like a table, so callers
+// falling back to a solver do not pay for the check on every call.
+func (c *TableCache) Get(ctx context.Context, sizes []int) (*Table, error) {
+	key := uniqueSizes(sizes)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sizes != nil && slices.Equal(c.sizes, key) {
+		return c.table, c.err
+	}
+
+	table, err := NewTableContext(ctx, key)
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return nil, err // not a property of the sizes, try again next time
+	}
+	c.sizes, c.table, c.err = key, table, err
+	return table, err
+}
+
+// Invalidate drops the cached table; the next Get rebuilds it.
+func (c *TableCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sizes, c.table, c.err = nil, nil, nil
+}
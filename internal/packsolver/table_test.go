@@ -0,0 +1,87 @@
+package packsolver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableMatchesDP(t *testing.T) {
+	sizeSets := [][]int{
+		{250, 500, 1000},
+		{23, 31, 53},
+		{6, 10, 15},
+		{4, 6},
+		{7},
+		{100, 250, 500, 1000, 2000, 5000},
+	}
+
+	for _, sizes := range sizeSets {
+		table, err := packsolver.NewTable(sizes)
+		assert.NoError(t, err)
+
+		for quantity := 1; quantity <= 4000; quantity += 7 {
+			wantPacks, wantTotal, err := packsolver.SolvePackDistribution(quantity, sizes)
+			assert.NoError(t, err)
+
+			packs, total, err := table.Solve(quantity)
+			assert.NoError(t, err)
+			assert.Equal(t, wantTotal, total, "quantity %d with %v", quantity, sizes)
+			assert.Equal(t, packsolver.TotalPacks(wantPacks), packsolver.TotalPacks(packs), "quantity %d with %v", quantity, sizes)
+
+			sum := 0
+			for _, p := range packs {
+				sum += p.Size * p.Count
+			}
+			assert.Equal(t, total, sum)
+		}
+	}
+}
+
+func TestTableLargeQuantity(t *testing.T) {
+	sizes := []int{23, 31, 53}
+	table, err := packsolver.NewTable(sizes)
+	assert.NoError(t, err)
+
+	wantPacks, wantTotal, err := packsolver.SolvePackDistribution(500000, sizes)
+	assert.NoError(t, err)
+	packs, total, err := table.Solve(500000)
+	assert.NoError(t, err)
+	assert.Equal(t, wantTotal, total)
+	assert.Equal(t, packsolver.TotalPacks(wantPacks), packsolver.TotalPacks(packs))
+
+	// far beyond anything the DP could handle
+	packs, total, err = table.Solve(1_000_000_000_000)
+	assert.NoError(t, err)
+	assert.Equal(t, 1_000_000_000_000, total)
+	assert.NotEmpty(t, packs)
+}
+
+func TestTableTooLarge(t *testing.T) {
+	_, err := packsolver.NewTable([]int{99991, 99989})
+	assert.ErrorIs(t, err, packsolver.ErrTableTooLarge)
+}
+
+func TestTableCache(t *testing.T) {
+	var cache packsolver.TableCache
+	ctx := context.Background()
+
+	first, err := cache.Get(ctx, []int{250, 500, 1000})
+	assert.NoError(t, err)
+
+	// same sizes in another order reuse the table
+	again, err := cache.Get(ctx, []int{1000, 250, 500})
+	assert.NoError(t, err)
+	assert.Same(t, first, again)
+
+	other, err := cache.Get(ctx, []int{23, 31, 53})
+	assert.NoError(t, err)
+	assert.NotSame(t, first, other)
+
+	cache.Invalidate()
+	rebuilt, err := cache.Get(ctx, []int{23, 31, 53})
+	assert.NoError(t, err)
+	assert.NotSame(t, other, rebuilt)
+}
@@ -0,0 +1,69 @@
+package packsolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrInvalidProposal is returned for a proposed distribution with a size or count <= 0.
+var ErrInvalidProposal = errors.New("proposed packs must have a size > 0 and a count >= 0")
+
+// Verification is the result of checking a proposed distribution against the configured
+// sizes and the optimal distribution.
+type Verification struct {
+	Valid        bool     `json:"valid"`                   // every size is configured and the packs cover the quantity
+	UnknownSizes []int    `json:"unknown_sizes,omitempty"` // proposed sizes that are not configured
+	Shortfall    int      `json:"shortfall,omitempty"`     // items missing to cover the quantity
+	TotalItems   int      `json:"total_items"`
+	TotalPacks   int      `json:"total_packs"`
+	Optimal      Solution `json:"optimal"`     // best distribution, as returned by SolvePackDistribution
+	ExtraItems   int      `json:"extra_items"` // TotalItems - Optimal.TotalItems
+	ExtraPacks   int      `json:"extra_packs"` // TotalPacks - Optimal.TotalPacks, negative when fewer packs are used
+	IsOptimal    bool     `json:"is_optimal"`  // valid and as good as the optimum
+}
+
+// Verify checks a proposed distribution, e.g. one changed by hand, and compares it with
+// the optimum for the quantity. An invalid proposal is reported in the result, errors are
+// reserved for input that cannot be checked at all.
+func Verify(quantity int, sizes []int, proposal []PackResult) (*Verification, error) {
+	return VerifyContext(context.Background(), quantity, sizes, proposal)
+}
+
+// VerifyContext is Verify that stops with a *TimeoutError once ctx is done.
+func VerifyContext(ctx context.Context, quantity int, sizes []int, proposal []PackResult) (*Verification, error) {
+	if err := validate(quantity, sizes); err != nil {
+		return nil, err
+	}
+
+	v := &Verification{}
+	for _, p := range proposal {
+		if p.Size <= 0 || p.Count < 0 {
+			return nil, fmt.Errorf("%w: got %d packs of %d", ErrInvalidProposal, p.Count, p.Size)
+		}
+		if p.Count == 0 {
+			continue
+		}
+		if !slices.Contains(sizes, p.Size) && !slices.Contains(v.UnknownSizes, p.Size) {
+			v.UnknownSizes = append(v.UnknownSizes, p.Size)
+		}
+		v.TotalItems += p.Size * p.Count
+		v.TotalPacks += p.Count
+	}
+	slices.Sort(v.UnknownSizes)
+	v.Shortfall = max(0, quantity-v.TotalItems)
+	v.Valid = len(v.UnknownSizes) == 0 && v.Shortfall == 0
+
+	// The branch-and-bound solver finds the same optimum as the DP without a table
+	// proportional to the quantity
+	packs, total, err := SolveBranchAndBoundContext(ctx, quantity, sizes)
+	if err != nil {
+		return nil, err
+	}
+	v.Optimal = Solution{Packs: packs, TotalItems: total, TotalPacks: TotalPacks(packs)}
+	v.ExtraItems = v.TotalItems - v.Optimal.TotalItems
+	v.ExtraPacks = v.TotalPacks - v.Optimal.TotalPacks
+	v.IsOptimal = v.Valid && !Better(v.Optimal.TotalItems, v.Optimal.TotalPacks, v.TotalItems, v.TotalPacks)
+	return v, nil
+}
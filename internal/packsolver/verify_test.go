@@ -0,0 +1,75 @@
+package packsolver_test
+
+import (
+	"testing"
+
+	"github.com/rapido-liebre/pack_solver/internal/packsolver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	sizes := []int{250, 500, 1000}
+
+	cases := []struct {
+		name       string
+		proposal   []packsolver.PackResult
+		valid      bool
+		optimal    bool
+		unknown    []int
+		shortfall  int
+		extraItems int
+		extraPacks int
+	}{
+		{
+			name:     "optimum",
+			proposal: []packsolver.PackResult{{Size: 1000, Count: 1}, {Size: 250, Count: 1}},
+			valid:    true, optimal: true,
+		},
+		{
+			name:     "same total, more packs",
+			proposal: []packsolver.PackResult{{Size: 500, Count: 2}, {Size: 250, Count: 1}},
+			valid:    true, extraPacks: 1,
+		},
+		{
+			name:     "short",
+			proposal: []packsolver.PackResult{{Size: 1000, Count: 1}, {Size: 250, Count: 0}},
+			valid:    false, shortfall: 201, extraItems: -250, extraPacks: -1,
+		},
+		{
+			name:     "overage",
+			proposal: []packsolver.PackResult{{Size: 500, Count: 3}},
+			valid:    true, extraItems: 250, extraPacks: 1,
+		},
+		{
+			name:     "unknown size",
+			proposal: []packsolver.PackResult{{Size: 1200, Count: 1}, {Size: 300, Count: 1}, {Size: 300, Count: 1}},
+			valid:    false, unknown: []int{300, 1200}, extraItems: 550, extraPacks: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		v, err := packsolver.Verify(1201, sizes, tc.proposal)
+		if !assert.NoError(t, err, tc.name) {
+			continue
+		}
+		assert.Equal(t, tc.valid, v.Valid, tc.name)
+		assert.Equal(t, tc.optimal, v.IsOptimal, tc.name)
+		assert.Equal(t, tc.unknown, v.UnknownSizes, tc.name)
+		assert.Equal(t, tc.shortfall, v.Shortfall, tc.name)
+		assert.Equal(t, tc.extraItems, v.ExtraItems, tc.name)
+		assert.Equal(t, tc.extraPacks, v.ExtraPacks, tc.name)
+		assert.Equal(t, 1250, v.Optimal.TotalItems, tc.name)
+		assert.Equal(t, 2, v.Optimal.TotalPacks, tc.name)
+	}
+}
+
+func TestVerifyInvalidProposal(t *testing.T) {
+	_, err := packsolver.Verify(100, []int{250}, []packsolver.PackResult{{Size: 250, Count: -1}})
+	assert.ErrorIs(t, err, packsolver.ErrInvalidProposal)
+
+	_, err = packsolver.Verify(100, []int{250}, []packsolver.PackResult{{Size: 0, Count: 1}})
+	assert.ErrorIs(t, err, packsolver.ErrInvalidProposal)
+
+	_, err = packsolver.Verify(0, []int{250}, nil)
+	assert.ErrorIs(t, err, packsolver.ErrInvalidQuantity)
+}
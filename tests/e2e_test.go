@@ -3,6 +3,7 @@ package tests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"github.com/rapido-liebre/pack_solver/internal/config"
 	"net/http"
@@ -10,8 +11,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	httpapi "github.com/rapido-liebre/pack_solver/internal/http"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type orderRequest struct {
@@ -27,21 +30,23 @@ type orderResponse struct {
 }
 
 func TestOrderEndpointE2E(t *testing.T) {
-	// Setup environment variables
-	_ = os.Setenv("REDIS_ADDR", "localhost:6379")
-	_ = os.Setenv("PACK_SOLVER_API", "http://localhost:8080")
+	// Setup environment variables, with Redis served by miniredis
+	redis := miniredis.RunT(t)
+	t.Setenv("REDIS_ADDR", redis.Addr())
+	t.Setenv("PACK_SOLVER_API", "http://localhost:8080")
 
 	// Initialize Redis
-	err := config.InitRedis()
-	assert.NoError(t, err)
+	store, err := config.NewRedisStore(os.Getenv("REDIS_ADDR"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
 
 	// Set sample pack sizes in Redis for test to succeed
-	err = config.SetPackSizes([]int{100, 250, 500, 1000})
+	_, err = store.SetPackConfig(context.Background(), "", config.PackConfig{Sizes: []int{100, 250, 500, 1000}}, config.Change{})
 	assert.NoError(t, err)
 
 	// Start backend server in goroutine
 	go func() {
-		r := httpapi.SetupRouter()
+		r := httpapi.SetupRouter(store)
 		_ = r.Run(":8080")
 	}()
 	// Wait for server to start